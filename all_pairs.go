@@ -0,0 +1,219 @@
+package dot
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// AllPairsMethod selects the algorithm used by AllPairs.
+type AllPairsMethod int
+
+const (
+	// AutoAllPairs picks FloydWarshall for dense graphs and Johnson for sparse ones.
+	AutoAllPairs AllPairsMethod = iota
+	// FloydWarshall runs in O(V³) time regardless of the number of edges.
+	FloydWarshall
+	// Johnson reweights edges through Bellman-Ford and runs Dijkstra from every vertex, in O(VE log V) time.
+	Johnson
+)
+
+// AllPairsResult holds the distance matrix and next hop table of an all-pairs shortest path computation.
+type AllPairsResult struct {
+	graph     *CompactGraph
+	distances [][]float64
+	next      [][]int
+}
+
+// AllPairs computes the shortest path distances between every pair of vertices using the configured costs.
+// Negative costs are supported; if the graph holds a negative cycle, a *NegativeCycleError is returned.
+func (g *Graph) AllPairs() (*AllPairsResult, error) {
+	return g.Compact().AllPairs(AutoAllPairs)
+}
+
+// AllPairs computes the shortest path distances between every pair of vertices using the precomputed costs of the
+// CompactGraph and the given method. See Graph.AllPairs.
+func (c *CompactGraph) AllPairs(method AllPairsMethod) (*AllPairsResult, error) {
+	if method == AutoAllPairs {
+		method = Johnson
+		// Floyd-Warshall pays off once E log V reaches V²
+		if float64(c.Size())*math.Log2(float64(c.Order())+1) >= float64(c.Order()*c.Order()) {
+			method = FloydWarshall
+		}
+	}
+	switch method {
+	case FloydWarshall:
+		return c.floydWarshall()
+	case Johnson:
+		return c.johnson()
+	}
+	return nil, fmt.Errorf("AllPairs(): unknown method %v", method)
+}
+
+func (c *CompactGraph) floydWarshall() (*AllPairsResult, error) {
+	result := c.newAllPairsResult()
+	order := c.Order()
+	for v := 0; v < order; v++ {
+		result.distances[v][v] = 0
+		result.next[v][v] = v
+		weights := c.Weights(v)
+		for i, target := range c.Neighbors(v) {
+			if weights[i] < result.distances[v][target] {
+				result.distances[v][target] = weights[i]
+				result.next[v][target] = target
+			}
+		}
+	}
+	for k := 0; k < order; k++ {
+		throughK := result.distances[k]
+		for i := 0; i < order; i++ {
+			toK := result.distances[i][k]
+			if math.IsInf(toK, 1) {
+				continue
+			}
+			row, nextRow := result.distances[i], result.next[i]
+			for j := 0; j < order; j++ {
+				if distance := toK + throughK[j]; distance < row[j] {
+					row[j] = distance
+					nextRow[j] = nextRow[k]
+				}
+			}
+		}
+	}
+	for v := 0; v < order; v++ {
+		if result.distances[v][v] < 0 {
+			// v lies on a negative cycle, which Bellman-Ford reports from v
+			_, err := c.BellmanFord(c.names[v])
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (c *CompactGraph) johnson() (*AllPairsResult, error) {
+	order := c.Order()
+	// potentials are the distances from a virtual vertex linked to every vertex with cost 0
+	potentials := make([]float64, order)
+	parents := make([]int, order)
+	for v := range parents {
+		parents[v] = -1
+	}
+	if vertex := c.relaxAll(potentials, parents); vertex != -1 {
+		return nil, &NegativeCycleError{Cycle: c.cycleThrough(vertex, parents)}
+	}
+	weights := make([]float64, len(c.weights))
+	for v := 0; v < order; v++ {
+		for edge := c.offsets[v]; edge < c.offsets[v+1]; edge++ {
+			weights[edge] = c.weights[edge] + potentials[v] - potentials[c.targets[edge]]
+		}
+	}
+
+	result := c.newAllPairsResult()
+	for source := 0; source < order; source++ {
+		distances, parents, settled := c.dijkstraFrom(source, weights, nil)
+		for _, v := range settled {
+			result.distances[source][v] = distances[v] - potentials[source] + potentials[v]
+			switch parents[v] {
+			case -1, source:
+				result.next[source][v] = v
+			default:
+				// vertices are settled after their predecessors, whose next hop is already known
+				result.next[source][v] = result.next[source][parents[v]]
+			}
+		}
+	}
+	return result, nil
+}
+
+func (c *CompactGraph) newAllPairsResult() *AllPairsResult {
+	order := c.Order()
+	result := &AllPairsResult{graph: c, distances: make([][]float64, order), next: make([][]int, order)}
+	for v := 0; v < order; v++ {
+		result.distances[v] = make([]float64, order)
+		result.next[v] = make([]int, order)
+		for w := 0; w < order; w++ {
+			result.distances[v][w] = math.Inf(1)
+			result.next[v][w] = -1
+		}
+	}
+	return result
+}
+
+// Vertices returns the names of the vertices of the result, in the order used by WriteCSV.
+func (r *AllPairsResult) Vertices() []string {
+	names := make([]string, len(r.graph.names))
+	copy(names, r.graph.names)
+	return names
+}
+
+// Distance returns the cost of the shortest path from origin to target, and whether target is reachable.
+func (r *AllPairsResult) Distance(origin string, target string) (float64, bool) {
+	o, t, ok := r.ids(origin, target)
+	if !ok || math.IsInf(r.distances[o][t], 1) {
+		return math.Inf(1), false
+	}
+	return r.distances[o][t], true
+}
+
+// NextHop returns the vertex following origin on the shortest path from origin to target, and whether target is
+// reachable. The next hop from a vertex to itself is the vertex itself.
+func (r *AllPairsResult) NextHop(origin string, target string) (string, bool) {
+	o, t, ok := r.ids(origin, target)
+	if !ok || r.next[o][t] == -1 {
+		return "", false
+	}
+	return r.graph.names[r.next[o][t]], true
+}
+
+// Path returns the shortest path from origin to target, following the next hop table, or ErrNoPath if target is
+// not reachable.
+func (r *AllPairsResult) Path(origin string, target string) (*Path, error) {
+	o, t, ok := r.ids(origin, target)
+	if !ok {
+		return nil, fmt.Errorf("Path() from %v to %v: vertex not found", origin, target)
+	}
+	if r.next[o][t] == -1 {
+		return nil, ErrNoPath
+	}
+	path := &Path{Vertices: []string{origin}, Cost: r.distances[o][t]}
+	for current := o; current != t; {
+		current = r.next[current][t]
+		path.Vertices = append(path.Vertices, r.graph.names[current])
+	}
+	return path, nil
+}
+
+// WriteCSV writes the distance matrix as CSV. The first row and column hold the vertex names; row vertices are
+// origins and column vertices targets. Cells of unreachable pairs are left empty.
+func (r *AllPairsResult) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	header := append([]string{""}, r.graph.names...)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for v, row := range r.distances {
+		record := make([]string, len(row)+1)
+		record[0] = r.graph.names[v]
+		for w, distance := range row {
+			if !math.IsInf(distance, 1) {
+				record[w+1] = strconv.FormatFloat(distance, 'g', -1, 64)
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func (r *AllPairsResult) ids(origin string, target string) (int, int, bool) {
+	o, exists := r.graph.index[origin]
+	if !exists {
+		return 0, 0, false
+	}
+	t, exists := r.graph.index[target]
+	return o, t, exists
+}
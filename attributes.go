@@ -0,0 +1,79 @@
+package dot
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// FloatAttribute reads attribute key from an attribute map as a float64. Integer values and strings holding
+// numbers (e.g. "2.0") are converted.
+func FloatAttribute(attributes map[string]interface{}, key string) (float64, error) {
+	value, exists := attributes[key]
+	if !exists {
+		return 0, fmt.Errorf("FloatAttribute() of attribute %v: attribute not found", key)
+	}
+	number, ok := toFloat64(value)
+	if !ok {
+		return 0, fmt.Errorf("FloatAttribute() of attribute %v: value %v is not a number", key, value)
+	}
+	return number, nil
+}
+
+// IntAttribute reads attribute key from an attribute map as an int. Float values are only accepted if integral.
+func IntAttribute(attributes map[string]interface{}, key string) (int, error) {
+	number, err := FloatAttribute(attributes, key)
+	if err != nil {
+		return 0, fmt.Errorf("IntAttribute() of attribute %v: %w", key, err)
+	}
+	// the conversion to int is only defined within its range, whose upper bound -MinInt is exact as a float
+	if number < float64(math.MinInt) || number >= -float64(math.MinInt) {
+		return 0, fmt.Errorf("IntAttribute() of attribute %v: value %v overflows int", key, number)
+	}
+	if number != float64(int(number)) {
+		return 0, fmt.Errorf("IntAttribute() of attribute %v: value %v is not an integer", key, number)
+	}
+	return int(number), nil
+}
+
+// StringAttribute reads attribute key from an attribute map as a string. Values of other types are formatted.
+func StringAttribute(attributes map[string]interface{}, key string) (string, error) {
+	value, exists := attributes[key]
+	if !exists {
+		return "", fmt.Errorf("StringAttribute() of attribute %v: attribute not found", key)
+	}
+	str, ok := value.(string)
+	if !ok {
+		return fmt.Sprint(value), nil
+	}
+	return str, nil
+}
+
+// BoolAttribute reads attribute key from an attribute map as a bool.
+func BoolAttribute(attributes map[string]interface{}, key string) (bool, error) {
+	value, exists := attributes[key]
+	if !exists {
+		return false, fmt.Errorf("BoolAttribute() of attribute %v: attribute not found", key)
+	}
+	boolean, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("BoolAttribute() of attribute %v: value %v is not a bool", key, value)
+	}
+	return boolean, nil
+}
+
+// toFloat64 converts the numeric values produced by the parser (float64 and int) to float64. Quoted numbers
+// such as "2.0" are kept as strings by the parser, so strings holding a number are converted as well.
+func toFloat64(value interface{}) (float64, bool) {
+	switch number := value.(type) {
+	case float64:
+		return number, true
+	case int:
+		return float64(number), true
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+		return parsed, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,137 @@
+package dot
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ShortestPathTree holds the result of a single source shortest path computation: the distance from the source
+// to every reachable vertex, and the predecessor of each vertex on its shortest path.
+type ShortestPathTree struct {
+	Source string
+
+	graph     *CompactGraph
+	distances []float64
+	parents   []int
+	reached   int
+}
+
+// NegativeCycleError is returned when a cycle whose total cost is negative is reachable from the source, which
+// makes shortest paths undefined. Cycle lists its vertices in order, starting and ending with the same vertex.
+type NegativeCycleError struct {
+	Cycle []string
+}
+
+func (e *NegativeCycleError) Error() string {
+	return fmt.Sprintf("negative cycle found: %v", strings.Join(e.Cycle, " -> "))
+}
+
+// BellmanFord computes the shortest paths from source to every other vertex using the configured costs, which may be
+// negative. If a negative cycle is reachable from source, a *NegativeCycleError describing it is returned.
+// In undirected graphs every edge is traversable both ways, so any negative edge forms a negative cycle.
+func (g *Graph) BellmanFord(source string) (*ShortestPathTree, error) {
+	return g.Compact().BellmanFord(source)
+}
+
+// BellmanFord computes the shortest paths from source to every other vertex using the precomputed costs of the
+// CompactGraph. See Graph.BellmanFord.
+func (c *CompactGraph) BellmanFord(source string) (*ShortestPathTree, error) {
+	sourceID, exists := c.index[source]
+	if !exists {
+		return nil, fmt.Errorf("BellmanFord() from %v: vertex not found", source)
+	}
+	order := c.Order()
+	distances := make([]float64, order)
+	parents := make([]int, order)
+	for v := range distances {
+		distances[v] = math.Inf(1)
+		parents[v] = -1
+	}
+	distances[sourceID] = 0
+	if vertex := c.relaxAll(distances, parents); vertex != -1 {
+		return nil, &NegativeCycleError{Cycle: c.cycleThrough(vertex, parents)}
+	}
+
+	tree := &ShortestPathTree{Source: source, graph: c, distances: distances, parents: parents}
+	for _, distance := range distances {
+		if !math.IsInf(distance, 1) {
+			tree.reached++
+		}
+	}
+	return tree, nil
+}
+
+// relaxAll runs the Bellman-Ford relaxation passes over distances and parents. If a negative cycle is reachable
+// from the vertices with a finite distance, it returns a vertex whose predecessors lead into the cycle; otherwise -1.
+func (c *CompactGraph) relaxAll(distances []float64, parents []int) int {
+	order := c.Order()
+	relaxed := true
+	for pass := 0; pass < order && relaxed; pass++ {
+		relaxed = false
+		for v := 0; v < order; v++ {
+			if math.IsInf(distances[v], 1) {
+				continue
+			}
+			weights := c.Weights(v)
+			for i, target := range c.Neighbors(v) {
+				if distance := distances[v] + weights[i]; distance < distances[target] {
+					distances[target] = distance
+					parents[target] = v
+					relaxed = true
+					// an improvement during the last pass can only come from a negative cycle
+					if pass == order-1 {
+						return target
+					}
+				}
+			}
+		}
+	}
+	return -1
+}
+
+// cycleThrough walks the predecessors of vertex until it is certainly inside the cycle, and returns the cycle.
+func (c *CompactGraph) cycleThrough(vertex int, parents []int) []string {
+	for i := 0; i < c.Order(); i++ {
+		vertex = parents[vertex]
+	}
+	cycle := []string{c.names[vertex]}
+	for current := parents[vertex]; current != vertex; current = parents[current] {
+		cycle = append(cycle, c.names[current])
+	}
+	cycle = append(cycle, c.names[vertex])
+	// predecessors were followed backwards, so reverse to obtain the direction of the edges
+	for i, j := 0, len(cycle)-1; i < j; i, j = i+1, j-1 {
+		cycle[i], cycle[j] = cycle[j], cycle[i]
+	}
+	return cycle
+}
+
+// Distance returns the cost of the shortest path from the source to vertex, and whether vertex is reachable.
+func (t *ShortestPathTree) Distance(vertex string) (float64, bool) {
+	id, exists := t.graph.index[vertex]
+	if !exists || math.IsInf(t.distances[id], 1) {
+		return math.Inf(1), false
+	}
+	return t.distances[id], true
+}
+
+// PathTo returns the shortest path from the source to vertex, or ErrNoPath if it is not reachable.
+// The Explored field of the path holds the number of vertices reachable from the source.
+func (t *ShortestPathTree) PathTo(vertex string) (*Path, error) {
+	id, exists := t.graph.index[vertex]
+	if !exists {
+		return nil, fmt.Errorf("PathTo() of vertex %v: vertex not found", vertex)
+	}
+	if math.IsInf(t.distances[id], 1) {
+		return nil, ErrNoPath
+	}
+	path := &Path{Cost: t.distances[id], Explored: t.reached}
+	for current := id; current != -1; current = t.parents[current] {
+		path.Vertices = append(path.Vertices, t.graph.names[current])
+	}
+	for i, j := 0, len(path.Vertices)-1; i < j; i, j = i+1, j-1 {
+		path.Vertices[i], path.Vertices[j] = path.Vertices[j], path.Vertices[i]
+	}
+	return path, nil
+}
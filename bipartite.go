@@ -0,0 +1,288 @@
+package dot
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// OddCycleError is returned when a graph is not bipartite. Cycle lists the vertices of a cycle of odd length in
+// order, starting and ending with the same vertex.
+type OddCycleError struct {
+	Cycle []string
+}
+
+func (e *OddCycleError) Error() string {
+	return fmt.Sprintf("graph is not bipartite, odd cycle found: %v", strings.Join(e.Cycle, " -- "))
+}
+
+// Bipartition splits the vertices of the graph into two sides such that every edge connects both sides, ignoring
+// the direction of edges. In every connected component, the alphabetically first vertex is placed on the left side.
+// Both sides are sorted alphabetically. If the graph is not bipartite, an *OddCycleError is returned.
+func (g *Graph) Bipartition() (left []string, right []string, err error) {
+	view := g.undirectedView()
+	sides, err := g.bipartition(view)
+	if err != nil {
+		return nil, nil, err
+	}
+	for v, side := range sides {
+		if side == 0 {
+			left = append(left, view.names[v])
+		} else {
+			right = append(right, view.names[v])
+		}
+	}
+	return left, right, nil
+}
+
+// bipartition colors every vertex of view with side 0 or 1 through breadth first search.
+func (g *Graph) bipartition(view *undirectedView) ([]int, error) {
+	// self loops are dropped from the view, but they are odd cycles on their own
+	for v, name := range view.names {
+		for _, neighbor := range g.adjacencyMap[name] {
+			if neighbor.Name() == name {
+				return nil, &OddCycleError{Cycle: []string{view.names[v], view.names[v]}}
+			}
+		}
+	}
+	sides := make([]int, len(view.names))
+	parents := make([]int, len(view.names))
+	for v := range sides {
+		sides[v] = -1
+	}
+	for root := range view.names {
+		if sides[root] != -1 {
+			continue
+		}
+		sides[root], parents[root] = 0, -1
+		for queue := []int{root}; len(queue) > 0; queue = queue[1:] {
+			v := queue[0]
+			for _, half := range view.adjacency[v] {
+				w := half.target
+				if sides[w] == -1 {
+					sides[w], parents[w] = 1-sides[v], v
+					queue = append(queue, w)
+				} else if sides[w] == sides[v] {
+					return nil, &OddCycleError{Cycle: view.oddCycle(v, w, parents)}
+				}
+			}
+		}
+	}
+	return sides, nil
+}
+
+// oddCycle joins the tree paths from v and w to their closest common ancestor through the edge v -- w. Both
+// vertices are on the same side, hence at the same depth, so the cycle has odd length.
+func (view *undirectedView) oddCycle(v int, w int, parents []int) []string {
+	var fromV, fromW []int
+	for v != w {
+		fromV, fromW = append(fromV, v), append(fromW, w)
+		v, w = parents[v], parents[w]
+	}
+	// v is now the common ancestor: walk down to the original v, cross the edge and climb back up from w
+	cycle := []string{view.names[v]}
+	for i := len(fromV) - 1; i >= 0; i-- {
+		cycle = append(cycle, view.names[fromV[i]])
+	}
+	for _, vertex := range fromW {
+		cycle = append(cycle, view.names[vertex])
+	}
+	return append(cycle, view.names[v])
+}
+
+// MaximumMatching returns a maximum set of edges of a bipartite graph no two of which share a vertex, computed with
+// the Hopcroft-Karp algorithm and ignoring the direction of edges. Edges lead from the left to the right side of
+// Bipartition, and are sorted by origin. If the graph is not bipartite, an *OddCycleError is returned.
+func (g *Graph) MaximumMatching() ([]Edge, error) {
+	view := g.undirectedView()
+	sides, err := g.bipartition(view)
+	if err != nil {
+		return nil, err
+	}
+	order := len(view.names)
+	matches := make([]int, order)
+	distances := make([]int, order)
+	for v := range matches {
+		matches[v] = -1
+	}
+	// bfs layers the free left vertices and their alternating paths, returning whether an augmenting path exists
+	bfs := func() bool {
+		var queue []int
+		found := false
+		for v := range matches {
+			distances[v] = -1
+			if sides[v] == 0 && matches[v] == -1 {
+				distances[v] = 0
+				queue = append(queue, v)
+			}
+		}
+		for ; len(queue) > 0; queue = queue[1:] {
+			v := queue[0]
+			for _, half := range view.adjacency[v] {
+				next := matches[half.target]
+				if next == -1 {
+					found = true
+				} else if distances[next] == -1 {
+					distances[next] = distances[v] + 1
+					queue = append(queue, next)
+				}
+			}
+		}
+		return found
+	}
+	var dfs func(v int) bool
+	dfs = func(v int) bool {
+		for _, half := range view.adjacency[v] {
+			next := matches[half.target]
+			if next == -1 || (distances[next] == distances[v]+1 && dfs(next)) {
+				matches[v], matches[half.target] = half.target, v
+				return true
+			}
+		}
+		// v leads to no augmenting path in this phase
+		distances[v] = -1
+		return false
+	}
+	for bfs() {
+		for v := range matches {
+			if sides[v] == 0 && matches[v] == -1 {
+				dfs(v)
+			}
+		}
+	}
+
+	var matching []Edge
+	for v, match := range matches {
+		if sides[v] == 0 && match != -1 {
+			matching = append(matching, Edge{Origin: view.names[v], Target: view.names[match]})
+		}
+	}
+	return matching, nil
+}
+
+// Assignment is the result of MinimumCostAssignment: the selected edges, leading from the left to the right side of
+// Bipartition and sorted by origin, and the sum of their costs.
+type Assignment struct {
+	Edges []WeightedEdge
+	Cost  float64
+}
+
+// MinimumCostAssignment solves the weighted assignment problem with the Hungarian algorithm: it returns a matching
+// of maximum size between both sides of Bipartition whose total cost, using the configured costs, is minimal. The
+// direction of edges is ignored, and only the cheapest of the edges between two vertices is considered.
+// If the graph is not bipartite, an *OddCycleError is returned.
+func (g *Graph) MinimumCostAssignment() (*Assignment, error) {
+	left, right, err := g.Bipartition()
+	if err != nil {
+		return nil, err
+	}
+	c := g.Compact()
+	costs := make(map[[2]int]float64)
+	bound := 0.0
+	for _, edge := range c.undirectedEdges() {
+		costs[[2]int{edge.origin, edge.target}] = edge.cost
+		bound += math.Abs(edge.cost)
+	}
+	// missing edges cost more than any difference between two assignments, so that the size of the assignment is
+	// maximized first
+	missing := 2*bound + 1
+	rows, columns := left, right
+	if len(rows) > len(columns) {
+		rows, columns = columns, rows
+	}
+	matrix := make([][]float64, len(rows))
+	for i, row := range rows {
+		matrix[i] = make([]float64, len(columns))
+		for j, column := range columns {
+			pair := [2]int{c.index[row], c.index[column]}
+			if pair[1] < pair[0] {
+				pair[0], pair[1] = pair[1], pair[0]
+			}
+			cost, exists := costs[pair]
+			if !exists {
+				cost = missing
+			}
+			matrix[i][j] = cost
+		}
+	}
+
+	assignment := &Assignment{}
+	for i, j := range hungarian(matrix) {
+		if matrix[i][j] == missing {
+			continue
+		}
+		edge := WeightedEdge{Edge: Edge{Origin: rows[i], Target: columns[j]}, Cost: matrix[i][j]}
+		if len(left) > len(right) {
+			edge.Origin, edge.Target = edge.Target, edge.Origin
+		}
+		assignment.Edges = append(assignment.Edges, edge)
+		assignment.Cost += edge.Cost
+	}
+	sort.Slice(assignment.Edges, func(i, j int) bool { return assignment.Edges[i].Origin < assignment.Edges[j].Origin })
+	return assignment, nil
+}
+
+// hungarian assigns every row of a cost matrix with no more rows than columns to a distinct column, minimizing the
+// total cost, in O(n²m) time. It returns the column assigned to each row.
+func hungarian(matrix [][]float64) []int {
+	n := len(matrix)
+	if n == 0 {
+		return nil
+	}
+	m := len(matrix[0])
+	// rows and columns are indexed from 1, with column 0 as a sentinel holding the row being inserted
+	rowPotentials := make([]float64, n+1)
+	columnPotentials := make([]float64, m+1)
+	owners := make([]int, m+1)
+	ways := make([]int, m+1)
+	for row := 1; row <= n; row++ {
+		owners[0] = row
+		column := 0
+		slack := make([]float64, m+1)
+		used := make([]bool, m+1)
+		for j := range slack {
+			slack[j] = math.Inf(1)
+		}
+		for {
+			used[column] = true
+			owner, delta, next := owners[column], math.Inf(1), 0
+			for j := 1; j <= m; j++ {
+				if used[j] {
+					continue
+				}
+				if current := matrix[owner-1][j-1] - rowPotentials[owner] - columnPotentials[j]; current < slack[j] {
+					slack[j], ways[j] = current, column
+				}
+				if slack[j] < delta {
+					delta, next = slack[j], j
+				}
+			}
+			for j := 0; j <= m; j++ {
+				if used[j] {
+					rowPotentials[owners[j]] += delta
+					columnPotentials[j] -= delta
+				} else {
+					slack[j] -= delta
+				}
+			}
+			column = next
+			if owners[column] == 0 {
+				break
+			}
+		}
+		// flip the alternating path ending at the free column
+		for column != 0 {
+			previous := ways[column]
+			owners[column] = owners[previous]
+			column = previous
+		}
+	}
+	assigned := make([]int, n)
+	for j := 1; j <= m; j++ {
+		if owners[j] != 0 {
+			assigned[owners[j]-1] = j - 1
+		}
+	}
+	return assigned
+}
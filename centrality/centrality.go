@@ -0,0 +1,163 @@
+// Package centrality computes centrality metrics over the vertices of a dot.Graph. Weighted metrics use the cost
+// configuration of the graph (CostKey, CostFunc and the default cost), which must not yield negative costs.
+package centrality
+
+import (
+	"math"
+	"sort"
+
+	"github.com/christat/dot"
+)
+
+// Scores maps every vertex name to its score.
+type Scores map[string]float64
+
+// Ranking returns the vertex names sorted by decreasing score, breaking ties alphabetically.
+func (s Scores) Ranking() []string {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if s[names[i]] != s[names[j]] {
+			return s[names[i]] > s[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// Store sets attribute on every vertex of g to its score, so that scores are kept when the graph is written as DOT.
+func Store(g *dot.Graph, scores Scores, attribute string) {
+	for name, score := range scores {
+		g.SetVertexAttribute(name, attribute, score)
+	}
+}
+
+// Degree returns the number of edges incident to every vertex, divided by the number of other vertices. In
+// digraphs, both incoming and outgoing edges are counted; an edge declared with "--" counts once per endpoint.
+func Degree(g *dot.Graph) Scores {
+	c := g.Compact()
+	degrees := make([]float64, c.Order())
+	counts := make(map[[2]int]int)
+	for v := 0; v < c.Order(); v++ {
+		for _, w := range c.Neighbors(v) {
+			degrees[v]++
+			counts[[2]int{v, w}]++
+		}
+	}
+	if g.Type == "digraph" {
+		// targets count incoming edges too, except the reverse entries stored for the edges declared with "--"
+		for pair, count := range counts {
+			origin, target := pair[0], pair[1]
+			if g.IsUndirectedEdge(c.VertexName(origin), c.VertexName(target)) {
+				if reverse := counts[[2]int{target, origin}]; reverse < count {
+					count -= reverse
+				} else {
+					count = 0
+				}
+			}
+			degrees[target] += float64(count)
+		}
+	}
+	return scores(c, degrees, float64(c.Order()-1))
+}
+
+// Closeness returns the closeness centrality of every vertex: the inverse of the average cost of the cheapest paths
+// leading from it to the vertices it reaches, scaled by the fraction of the other vertices it reaches, so that
+// scores remain comparable in disconnected graphs. Vertices which reach no other vertex score 0.
+func Closeness(g *dot.Graph) Scores {
+	c := g.Compact()
+	closeness := make([]float64, c.Order())
+	for source := 0; source < c.Order(); source++ {
+		distances, _, _, _ := c.ShortestPathCounts(source)
+		reached, total := 0, 0.0
+		for _, distance := range distances {
+			if !math.IsInf(distance, 1) {
+				reached++
+				total += distance
+			}
+		}
+		if reached > 1 && total > 0 {
+			others := float64(reached - 1)
+			closeness[source] = others / total * others / float64(c.Order()-1)
+		}
+	}
+	return scores(c, closeness, 1)
+}
+
+// Betweenness returns the betweenness centrality of every vertex, computed with Brandes' algorithm: the fraction of
+// the cheapest paths between every other pair of vertices going through it, divided by the number of such pairs.
+func Betweenness(g *dot.Graph) Scores {
+	c := g.Compact()
+	betweenness := make([]float64, c.Order())
+	for source := 0; source < c.Order(); source++ {
+		_, counts, predecessors, settled := c.ShortestPathCounts(source)
+		dependencies := make([]float64, c.Order())
+		// vertices are settled by increasing distance, so walking them backwards accumulates dependencies in order
+		for i := len(settled) - 1; i >= 0; i-- {
+			w := settled[i]
+			for _, v := range predecessors[w] {
+				dependencies[v] += counts[v] / counts[w] * (1 + dependencies[w])
+			}
+			if w != source {
+				betweenness[w] += dependencies[w]
+			}
+		}
+	}
+	// in undirected graphs every pair is counted in both directions, which the normalization accounts for
+	return scores(c, betweenness, float64((c.Order()-1)*(c.Order()-2)))
+}
+
+// PageRank returns the PageRank of every vertex, following edges regardless of their costs. damping is the
+// probability of following an edge rather than jumping to a random vertex, usually 0.85. Vertices without outgoing
+// edges distribute their rank evenly. Iteration stops once ranks change by less than 1e-10 overall, or after 1000
+// iterations. Ranks add up to 1.
+func PageRank(g *dot.Graph, damping float64) Scores {
+	c := g.Compact()
+	order := float64(c.Order())
+	ranks := make([]float64, c.Order())
+	for v := range ranks {
+		ranks[v] = 1 / order
+	}
+	next := make([]float64, c.Order())
+	for iteration := 0; iteration < 1000; iteration++ {
+		dangling := 0.0
+		for v := range next {
+			next[v] = 0
+		}
+		for v := 0; v < c.Order(); v++ {
+			neighbors := c.Neighbors(v)
+			if len(neighbors) == 0 {
+				dangling += ranks[v]
+				continue
+			}
+			share := ranks[v] / float64(len(neighbors))
+			for _, w := range neighbors {
+				next[w] += share
+			}
+		}
+		change := 0.0
+		for v := range next {
+			next[v] = (1-damping)/order + damping*(next[v]+dangling/order)
+			change += math.Abs(next[v] - ranks[v])
+		}
+		ranks, next = next, ranks
+		if change < 1e-10 {
+			break
+		}
+	}
+	return scores(c, ranks, 1)
+}
+
+// scores maps the values indexed by vertex ID to vertex names, dividing them by scale when it is positive.
+func scores(c *dot.CompactGraph, values []float64, scale float64) Scores {
+	result := make(Scores, len(values))
+	for v, value := range values {
+		if scale > 0 {
+			value /= scale
+		}
+		result[c.VertexName(v)] = value
+	}
+	return result
+}
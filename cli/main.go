@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/christat/dot"
 	"os"
+	"strings"
 )
 
 const (
@@ -13,10 +14,24 @@ const (
 )
 
 func main() {
+	// subcommands take their own parameters
+	if len(os.Args) > 1 && os.Args[1] == "analyze" {
+		os.Exit(analyze(os.Args[0], os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "maxflow" {
+		os.Exit(maxFlow(os.Args[0], os.Args[2:]))
+	}
+
 	// definition of CLI parameters
 	filePath := flag.String("f", "", "path to .dot file containing the graph definition\n")
 	inspect := flag.Bool("i", false, "inspection mode. prints the parsed graph's attributes\n")
 	verbose := flag.Bool("v", false, "verbose mode. If set, control statements are printed during parsing\n")
+	costKey := flag.String("cost", "", "edge attribute holding the cost of the edges, used by path queries\n")
+	from := flag.String("from", "", "origin vertex of the paths printed with -k\n")
+	to := flag.String("to", "", "target vertex of the paths printed with -k\n")
+	k := flag.Int("k", 0, "prints the k cheapest loopless paths between the vertices given by -from and -to\n")
+	reduce := flag.Bool("reduce", false, "prints the transitive reduction of the graph as DOT which -f can read back\n")
+	allPairs := flag.Bool("allpairs", false, "prints the all-pairs shortest path distance matrix as CSV\n")
 	flag.Parse()
 
 	//get CLI program exec name
@@ -36,6 +51,8 @@ func main() {
 		os.Exit(exitError)
 	}
 
+	g.CostKey = *costKey
+
 	// if attribute inspection has been selected
 	if *inspect {
 		vertices := g.AdjacencyMap()
@@ -86,5 +103,111 @@ func main() {
 			}
 		}
 	}
+
+	// if the k shortest paths have been requested
+	if *k > 0 {
+		paths, err := g.KShortestPaths(*from, *to, *k)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to find paths from %v to %v: %v\n", *from, *to, err)
+			os.Exit(exitError)
+		}
+		for i, path := range paths {
+			fmt.Printf("%v. %v (cost %v)\n", i+1, strings.Join(path.Vertices, " -> "), path.Cost)
+		}
+	}
+
+	// if the all-pairs distance matrix has been requested
+	if *allPairs {
+		result, err := g.AllPairs()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to compute all-pairs shortest paths: %v\n", err)
+			os.Exit(exitError)
+		}
+		if err = result.WriteCSV(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write distance matrix: %v\n", err)
+			os.Exit(exitError)
+		}
+	}
+
+	// if the transitive reduction has been requested
+	if *reduce {
+		reduced, err := g.TransitiveReduction()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to reduce graph: %v\n", err)
+			os.Exit(exitError)
+		}
+		if err = reduced.WriteParsableDOT(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write graph: %v\n", err)
+			os.Exit(exitError)
+		}
+	}
 	os.Exit(exitSuccess)
 }
+
+// analyze prints the connected components, articulation points and bridges of a graph, returning the exit code.
+func analyze(programName string, args []string) int {
+	flags := flag.NewFlagSet("analyze", flag.ExitOnError)
+	filePath := flags.String("f", "", "path to .dot file containing the graph definition\n")
+	verbose := flags.Bool("v", false, "verbose mode. If set, control statements are printed during parsing\n")
+	flags.Parse(args)
+	if !(len(*filePath) > 0) {
+		fmt.Fprintf(os.Stderr, "Please, provide a .dot file through argument -f.\nsee %v analyze -help for more details\n",
+			programName)
+		return exitError
+	}
+	ok, g := dot.ParseFile(*filePath, *verbose)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Failed to parse file %v. Please check file and try verbose mode (-v) to assess any errors.\n", *filePath)
+		return exitError
+	}
+
+	components := g.ConnectedComponents()
+	fmt.Printf("Connected components: %v\n", len(components))
+	for _, component := range components {
+		fmt.Printf("\t%v\n", strings.Join(component, ", "))
+	}
+	fmt.Printf("Articulation points: %v\n", strings.Join(g.ArticulationPoints(), ", "))
+	bridges := g.Bridges()
+	fmt.Printf("Bridges: %v\n", len(bridges))
+	for _, bridge := range bridges {
+		fmt.Printf("\t%v -- %v\n", bridge.Origin, bridge.Target)
+	}
+	return exitSuccess
+}
+
+// maxFlow prints the graph as DOT with the flow of a maximum flow stored as edge attributes, preceded by comments
+// holding the flow value and the minimum cut. It returns the exit code.
+func maxFlow(programName string, args []string) int {
+	flags := flag.NewFlagSet("maxflow", flag.ExitOnError)
+	filePath := flags.String("f", "", "path to .dot file containing the graph definition\n")
+	verbose := flags.Bool("v", false, "verbose mode. If set, control statements are printed during parsing\n")
+	source := flags.String("source", "", "vertex the flow leaves from\n")
+	sink := flags.String("sink", "", "vertex the flow arrives to\n")
+	capacityKey := flags.String("capacity", "capacity", "edge attribute holding the capacity of the edges\n")
+	flowKey := flags.String("attr", "flow", "edge attribute the flow is written to\n")
+	flags.Parse(args)
+	if !(len(*filePath) > 0) {
+		fmt.Fprintf(os.Stderr, "Please, provide a .dot file through argument -f.\nsee %v maxflow -help for more details\n",
+			programName)
+		return exitError
+	}
+	ok, g := dot.ParseFile(*filePath, *verbose)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Failed to parse file %v. Please check file and try verbose mode (-v) to assess any errors.\n", *filePath)
+		return exitError
+	}
+
+	result, err := g.MaxFlow(*source, *sink, *capacityKey)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to compute maximum flow: %v\n", err)
+		return exitError
+	}
+	result.Store(*flowKey)
+	fmt.Printf("// max flow: %v\n", result.Value)
+	fmt.Printf("// min cut: %v | %v\n", strings.Join(result.SourceSide, ", "), strings.Join(result.SinkSide, ", "))
+	if err = g.WriteDOT(os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write graph: %v\n", err)
+		return exitError
+	}
+	return exitSuccess
+}
@@ -0,0 +1,410 @@
+package dot
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ColoringAlgorithm selects the algorithm used by Color.
+type ColoringAlgorithm int
+
+const (
+	// Greedy colors vertices by decreasing degree (Welsh-Powell order), giving each the smallest color not used by
+	// its neighbors.
+	Greedy ColoringAlgorithm = iota
+	// DSatur repeatedly colors the vertex whose neighbors already use the most distinct colors. It usually needs
+	// fewer colors than Greedy, and is optimal on bipartite graphs.
+	DSatur
+	// ExactColoring finds a coloring with the fewest possible colors by branch and bound, which takes exponential
+	// time in the worst case. Use the limits of ColoringOptions to bound it.
+	ExactColoring
+)
+
+// coloringPalette holds the colors written by Coloring.Store. Further colors are spread over the hue circle.
+var coloringPalette = []string{
+	"lightblue", "lightcoral", "palegreen", "gold", "plum", "sandybrown",
+	"turquoise", "lightpink", "khaki", "lightslateblue", "yellowgreen", "tan",
+}
+
+// ColoringOptions configures a Color query. The limits only apply to ExactColoring.
+type ColoringOptions struct {
+	Algorithm ColoringAlgorithm
+	// Timeout, if positive, abandons the exact search after that duration.
+	Timeout time.Duration
+	// MaxVertices, if positive, rejects graphs with more vertices without searching.
+	MaxVertices int
+}
+
+// Coloring is the result of Color: the color of every vertex, numbered from 0, and the number of colors used.
+type Coloring struct {
+	Colors map[string]int
+	Count  int
+
+	graph *Graph
+}
+
+// Color assigns a color to every vertex of the graph so that adjacent vertices get different colors, e.g. the
+// registers of an interference graph. The direction of edges and self loops are ignored. Vertices are considered in
+// alphabetical order on ties, so results are deterministic.
+// When the exact search reaches a limit of opts, an error wrapping ErrSearchLimit is returned.
+func (g *Graph) Color(opts ColoringOptions) (*Coloring, error) {
+	neighbors := g.simpleNeighbors()
+	var colors []int
+	switch opts.Algorithm {
+	case Greedy:
+		colors = greedyColoring(neighbors)
+	case DSatur:
+		colors = dsaturColoring(neighbors)
+	case ExactColoring:
+		if opts.MaxVertices > 0 && len(neighbors) > opts.MaxVertices {
+			return nil, fmt.Errorf("Color() of graph %v with %v vertices: %w", g.Name, len(neighbors), ErrSearchLimit)
+		}
+		var err error
+		if colors, err = exactColoring(neighbors, opts.Timeout); err != nil {
+			return nil, fmt.Errorf("Color() of graph %v: %w", g.Name, err)
+		}
+	default:
+		return nil, fmt.Errorf("Color(): unknown algorithm %v", opts.Algorithm)
+	}
+
+	names := g.topology().names
+	coloring := &Coloring{Colors: make(map[string]int, len(names)), graph: g}
+	for v, color := range colors {
+		coloring.Colors[names[v]] = color
+		if color >= coloring.Count {
+			coloring.Count = color + 1
+		}
+	}
+	return coloring, nil
+}
+
+// Classes returns the vertices of every color, sorted alphabetically and indexed by color.
+func (c *Coloring) Classes() [][]string {
+	classes := make([][]string, c.Count)
+	for name, color := range c.Colors {
+		classes[color] = append(classes[color], name)
+	}
+	for _, class := range classes {
+		sort.Strings(class)
+	}
+	return classes
+}
+
+// Store sets the color and fillcolor attributes of every vertex of the graph Color was run on to the name of its
+// color, so that Graphviz draws each color class alike. Vertices without a style attribute get style=filled, as
+// Graphviz ignores fillcolor otherwise.
+func (c *Coloring) Store() {
+	for name, color := range c.Colors {
+		value := ColorName(color)
+		c.graph.SetVertexAttribute(name, "color", value)
+		c.graph.SetVertexAttribute(name, "fillcolor", value)
+		if _, err := c.graph.GetVertexAttribute(name, "style"); err != nil {
+			c.graph.SetVertexAttribute(name, "style", "filled")
+		}
+	}
+}
+
+// ColorName returns the Graphviz color written by Coloring.Store for the given color number: an X11 color name for
+// the first colors, then an HSV triple.
+func ColorName(color int) string {
+	if color < len(coloringPalette) {
+		return coloringPalette[color]
+	}
+	// golden ratio steps keep consecutive hues apart
+	hue := float64(color-len(coloringPalette)) * 0.618033988749895
+	return fmt.Sprintf("%.3f 0.450 0.950", hue-float64(int(hue)))
+}
+
+// simpleNeighbors returns, for every vertex ID of the topology of g, its distinct neighbors regardless of the direction
+// of edges, sorted, and without the vertex itself.
+func (g *Graph) simpleNeighbors() [][]int {
+	view := g.undirectedView()
+	neighbors := make([][]int, len(view.names))
+	for v, halves := range view.adjacency {
+		for _, half := range halves {
+			neighbors[v] = append(neighbors[v], half.target)
+		}
+		sort.Ints(neighbors[v])
+		neighbors[v] = uniqueInts(neighbors[v])
+	}
+	return neighbors
+}
+
+func greedyColoring(neighbors [][]int) []int {
+	order := make([]int, len(neighbors))
+	for v := range order {
+		order[v] = v
+	}
+	sort.SliceStable(order, func(i, j int) bool { return len(neighbors[order[i]]) > len(neighbors[order[j]]) })
+	colors := make([]int, len(neighbors))
+	for v := range colors {
+		colors[v] = -1
+	}
+	for _, v := range order {
+		colors[v] = smallestFreeColor(neighbors[v], colors)
+	}
+	return colors
+}
+
+func smallestFreeColor(neighbors []int, colors []int) int {
+	taken := make(map[int]bool, len(neighbors))
+	for _, w := range neighbors {
+		if colors[w] >= 0 {
+			taken[colors[w]] = true
+		}
+	}
+	color := 0
+	for taken[color] {
+		color++
+	}
+	return color
+}
+
+func dsaturColoring(neighbors [][]int) []int {
+	colors := make([]int, len(neighbors))
+	for v := range colors {
+		colors[v] = -1
+	}
+	saturation := make([]map[int]bool, len(neighbors))
+	for v := range saturation {
+		saturation[v] = make(map[int]bool)
+	}
+	for range neighbors {
+		v := mostSaturated(neighbors, colors, saturation)
+		colors[v] = smallestFreeColor(neighbors[v], colors)
+		for _, w := range neighbors[v] {
+			saturation[w][colors[v]] = true
+		}
+	}
+	return colors
+}
+
+// mostSaturated returns the uncolored vertex with the most distinct colors among its neighbors, breaking ties by
+// degree and then by ID.
+func mostSaturated(neighbors [][]int, colors []int, saturation []map[int]bool) int {
+	best := -1
+	for v := range neighbors {
+		if colors[v] >= 0 {
+			continue
+		}
+		if best == -1 || len(saturation[v]) > len(saturation[best]) ||
+			len(saturation[v]) == len(saturation[best]) && len(neighbors[v]) > len(neighbors[best]) {
+			best = v
+		}
+	}
+	return best
+}
+
+// exactColoring improves the DSatur coloring by branch and bound, stopping early once it matches the size of a
+// clique, which no coloring can beat. The clique is found greedily, as finding a maximum one takes exponential time
+// which the timeout would not bound.
+func exactColoring(neighbors [][]int, timeout time.Duration) ([]int, error) {
+	search := &coloringSearch{neighbors: neighbors, best: dsaturColoring(neighbors)}
+	if timeout > 0 {
+		search.deadline = time.Now().Add(timeout)
+	}
+	for _, color := range search.best {
+		if color >= search.bestCount {
+			search.bestCount = color + 1
+		}
+	}
+	search.lowerBound = greedyClique(neighbors)
+	search.colors = make([]int, len(neighbors))
+	for v := range search.colors {
+		search.colors[v] = -1
+	}
+	search.extend(0, 0)
+	if search.exceeded {
+		return nil, ErrSearchLimit
+	}
+	return search.best, nil
+}
+
+type coloringSearch struct {
+	neighbors  [][]int
+	colors     []int
+	best       []int
+	bestCount  int
+	lowerBound int
+	deadline   time.Time
+	steps      int
+	exceeded   bool
+}
+
+// extend colors one more vertex, given the number of vertices colored and colors used so far. It returns true once
+// the search can stop, i.e. the lower bound was reached or a limit was exceeded.
+func (s *coloringSearch) extend(colored int, used int) bool {
+	s.steps++
+	if !s.deadline.IsZero() && s.steps%1024 == 0 && time.Now().After(s.deadline) {
+		s.exceeded = true
+	}
+	if s.exceeded || s.bestCount <= s.lowerBound {
+		return true
+	}
+	if colored == len(s.colors) {
+		s.best = append([]int{}, s.colors...)
+		s.bestCount = used
+		return s.bestCount <= s.lowerBound
+	}
+
+	saturation := make([]map[int]bool, len(s.colors))
+	for v := range saturation {
+		saturation[v] = make(map[int]bool)
+		for _, w := range s.neighbors[v] {
+			if s.colors[w] >= 0 {
+				saturation[v][s.colors[w]] = true
+			}
+		}
+	}
+	v := mostSaturated(s.neighbors, s.colors, saturation)
+	// trying a single new color avoids exploring colorings which only differ by a permutation of colors
+	for color := 0; color <= used && color < s.bestCount-1; color++ {
+		if saturation[v][color] {
+			continue
+		}
+		s.colors[v] = color
+		next := used
+		if color == used {
+			next++
+		}
+		if s.extend(colored+1, next) {
+			s.colors[v] = -1
+			return true
+		}
+		s.colors[v] = -1
+	}
+	return false
+}
+
+// MaximalCliques enumerates the maximal cliques of the graph with the Bron-Kerbosch algorithm, with pivoting: the
+// sets of pairwise adjacent vertices which can't be extended with another vertex. The direction of edges and self
+// loops are ignored. Each clique is sorted alphabetically, and cliques are sorted by their vertices.
+func (g *Graph) MaximalCliques() [][]string {
+	names := g.topology().names
+	var cliques [][]string
+	bronKerbosch(g.simpleNeighbors(), func(clique []int) {
+		members := make([]string, len(clique))
+		for i, v := range clique {
+			members[i] = names[v]
+		}
+		sort.Strings(members)
+		cliques = append(cliques, members)
+	})
+	sort.Slice(cliques, func(i, j int) bool {
+		for k := 0; k < len(cliques[i]) && k < len(cliques[j]); k++ {
+			if cliques[i][k] != cliques[j][k] {
+				return cliques[i][k] < cliques[j][k]
+			}
+		}
+		return len(cliques[i]) < len(cliques[j])
+	})
+	return cliques
+}
+
+// MaximumClique returns a largest clique of the graph, sorted alphabetically; the first one in the order of
+// MaximalCliques if several have the same size. See MaximalCliques.
+func (g *Graph) MaximumClique() []string {
+	var largest []string
+	for _, clique := range g.MaximalCliques() {
+		if len(clique) > len(largest) {
+			largest = clique
+		}
+	}
+	return largest
+}
+
+// greedyClique returns the size of the largest clique grown from each vertex in turn by adding its neighbors by
+// decreasing degree, whenever they are adjacent to every member so far.
+func greedyClique(neighbors [][]int) int {
+	adjacent := make([]bitset, len(neighbors))
+	for v := range neighbors {
+		adjacent[v] = newBitset(len(neighbors))
+		for _, w := range neighbors[v] {
+			adjacent[v].add(w)
+		}
+	}
+	largest := 0
+	for v := range neighbors {
+		candidates := append([]int{}, neighbors[v]...)
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return len(neighbors[candidates[i]]) > len(neighbors[candidates[j]])
+		})
+		clique := []int{v}
+		for _, w := range candidates {
+			member := true
+			for _, u := range clique {
+				if !adjacent[u].has(w) {
+					member = false
+					break
+				}
+			}
+			if member {
+				clique = append(clique, w)
+			}
+		}
+		if len(clique) > largest {
+			largest = len(clique)
+		}
+	}
+	return largest
+}
+
+// bronKerbosch calls found with every maximal clique. The clique slice is reused between calls.
+func bronKerbosch(neighbors [][]int, found func(clique []int)) {
+	adjacent := make([]bitset, len(neighbors))
+	candidates := make([]int, len(neighbors))
+	for v := range neighbors {
+		adjacent[v] = newBitset(len(neighbors))
+		for _, w := range neighbors[v] {
+			adjacent[v].add(w)
+		}
+		candidates[v] = v
+	}
+	var clique []int
+	var expand func(candidates []int, excluded []int)
+	expand = func(candidates []int, excluded []int) {
+		if len(candidates) == 0 {
+			if len(excluded) == 0 && len(clique) > 0 {
+				found(clique)
+			}
+			return
+		}
+		// the pivot has the most neighbors among candidates, which then don't need to be tried
+		pivot, covered := -1, -1
+		for _, u := range append(append([]int{}, candidates...), excluded...) {
+			count := 0
+			for _, v := range candidates {
+				if adjacent[u].has(v) {
+					count++
+				}
+			}
+			if count > covered {
+				pivot, covered = u, count
+			}
+		}
+		for i := 0; i < len(candidates); i++ {
+			v := candidates[i]
+			if adjacent[pivot].has(v) {
+				continue
+			}
+			clique = append(clique, v)
+			expand(intersectAdjacent(candidates, adjacent[v]), intersectAdjacent(excluded, adjacent[v]))
+			clique = clique[:len(clique)-1]
+			candidates = append(candidates[:i:i], candidates[i+1:]...)
+			excluded = append(excluded, v)
+			i--
+		}
+	}
+	expand(candidates, nil)
+}
+
+func intersectAdjacent(vertices []int, adjacent bitset) []int {
+	var result []int
+	for _, v := range vertices {
+		if adjacent.has(v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,303 @@
+package dot
+
+import (
+	"fmt"
+	"sort"
+)
+
+// CommunityAlgorithm selects the algorithm used by Communities.
+type CommunityAlgorithm int
+
+const (
+	// Louvain greedily maximizes modularity, moving vertices between communities and merging communities into
+	// single vertices until no move improves it.
+	Louvain CommunityAlgorithm = iota
+	// LabelPropagation repeatedly assigns every vertex the label most common among its neighbors. It is faster than
+	// Louvain but usually finds communities of lower modularity.
+	LabelPropagation
+)
+
+// CommunityOptions configures a Communities query.
+type CommunityOptions struct {
+	Algorithm CommunityAlgorithm
+	// WeightKey, if set, names the edge attribute holding the strength of the edges. Edges without it weigh 1.
+	WeightKey string
+}
+
+// Communities partitions the vertices of the graph into communities of densely connected vertices, ignoring the
+// direction of edges. Vertices are processed in alphabetical order, so results are deterministic. Each community
+// lists its vertices sorted alphabetically, and communities are ordered by their first vertex.
+// An error is returned if a weight is not a non-negative number.
+func (g *Graph) Communities(opts CommunityOptions) ([][]string, error) {
+	view := g.undirectedView()
+	links, err := g.communityLinks(view, opts.WeightKey)
+	if err != nil {
+		return nil, err
+	}
+	var membership []int
+	switch opts.Algorithm {
+	case Louvain:
+		membership = louvain(links)
+	case LabelPropagation:
+		membership = labelPropagation(links)
+	default:
+		return nil, fmt.Errorf("Communities(): unknown algorithm %v", opts.Algorithm)
+	}
+	return groupMembers(view.names, membership), nil
+}
+
+// Modularity returns the modularity of a partition of the vertices of the graph into communities, ignoring the
+// direction of edges: the fraction of the weight of the edges falling within communities, minus the fraction
+// expected if edges were placed at random. Vertices missing from communities form a community of their own each.
+func (g *Graph) Modularity(communities [][]string, weightKey string) (float64, error) {
+	view := g.undirectedView()
+	links, err := g.communityLinks(view, weightKey)
+	if err != nil {
+		return 0, err
+	}
+	index := make(map[string]int, len(view.names))
+	for v, name := range view.names {
+		index[name] = v
+	}
+	membership := make([]int, len(view.names))
+	for v := range membership {
+		membership[v] = len(communities) + v
+	}
+	for c, members := range communities {
+		for _, member := range members {
+			if v, exists := index[member]; exists {
+				membership[v] = c
+			}
+		}
+	}
+	return modularity(links, membership), nil
+}
+
+// communityLink is an undirected edge as seen from one of its endpoints. A link from a vertex to itself stands for
+// the weight of the edges within an aggregated community, counted once per endpoint.
+type communityLink struct {
+	target int
+	weight float64
+}
+
+// communityLinks reads the weights of the edges of view, stored once at each endpoint.
+func (g *Graph) communityLinks(view *undirectedView, weightKey string) ([][]communityLink, error) {
+	links := make([][]communityLink, len(view.names))
+	for v, halves := range view.adjacency {
+		for _, half := range halves {
+			weight, err := g.communityWeight(view.names[v], view.names[half.target], weightKey)
+			if err != nil {
+				return nil, err
+			}
+			links[v] = append(links[v], communityLink{target: half.target, weight: weight})
+		}
+	}
+	return links, nil
+}
+
+// communityWeight reads the weight of the undirected edge between origin and target from the attribute weightKey of
+// either direction, defaulting to 1.
+func (g *Graph) communityWeight(origin string, target string, weightKey string) (float64, error) {
+	if weightKey == "" {
+		return 1, nil
+	}
+	for _, attributes := range []map[string]interface{}{g.edgeAttributes[origin][target], g.edgeAttributes[target][origin]} {
+		if value, exists := attributes[weightKey]; exists {
+			weight, err := FloatAttribute(attributes, weightKey)
+			if err != nil || weight < 0 {
+				return 0, fmt.Errorf("Communities() of edge %v -- %v: weight %v is not a non-negative number",
+					origin, target, value)
+			}
+			return weight, nil
+		}
+	}
+	return 1, nil
+}
+
+// louvain returns the community of every vertex of links, numbered from 0.
+func louvain(links [][]communityLink) []int {
+	membership := make([]int, len(links))
+	for v := range membership {
+		membership[v] = v
+	}
+	for {
+		communities, moved := moveVertices(links)
+		if !moved {
+			return membership
+		}
+		for v := range membership {
+			membership[v] = communities[membership[v]]
+		}
+		links = aggregate(links, communities)
+	}
+}
+
+// moveVertices runs the local moving phase of Louvain, returning the community of every vertex, renumbered in order
+// of appearance, and whether any vertex changed community.
+func moveVertices(links [][]communityLink) ([]int, bool) {
+	order := len(links)
+	communities := make([]int, order)
+	degrees := make([]float64, order)
+	totals := make([]float64, order)
+	total := 0.0
+	for v := range links {
+		communities[v] = v
+		for _, link := range links[v] {
+			degrees[v] += link.weight
+		}
+		totals[v] = degrees[v]
+		total += degrees[v]
+	}
+	if total == 0 {
+		return communities, false
+	}
+
+	moved := false
+	for improved := true; improved; {
+		improved = false
+		for v := 0; v < order; v++ {
+			current := communities[v]
+			totals[current] -= degrees[v]
+			shared := make(map[int]float64)
+			for _, link := range links[v] {
+				if link.target != v {
+					shared[communities[link.target]] += link.weight
+				}
+			}
+			// the gain of joining a community is proportional to shared - totals * degree / total
+			best, bestGain := current, shared[current]-totals[current]*degrees[v]/total
+			candidates := make([]int, 0, len(shared))
+			for community := range shared {
+				candidates = append(candidates, community)
+			}
+			sort.Ints(candidates)
+			for _, community := range candidates {
+				if gain := shared[community] - totals[community]*degrees[v]/total; gain > bestGain+1e-12 {
+					best, bestGain = community, gain
+				}
+			}
+			totals[best] += degrees[v]
+			if best != current {
+				communities[v] = best
+				improved, moved = true, true
+			}
+		}
+	}
+	return renumber(communities), moved
+}
+
+// aggregate merges the vertices of every community of links into a single vertex.
+func aggregate(links [][]communityLink, communities []int) [][]communityLink {
+	count := 0
+	for _, community := range communities {
+		if community+1 > count {
+			count = community + 1
+		}
+	}
+	weights := make([]map[int]float64, count)
+	for c := range weights {
+		weights[c] = make(map[int]float64)
+	}
+	for v, vertexLinks := range links {
+		for _, link := range vertexLinks {
+			weights[communities[v]][communities[link.target]] += link.weight
+		}
+	}
+	aggregated := make([][]communityLink, count)
+	for c, targets := range weights {
+		for _, target := range sortedIntKeys(targets) {
+			aggregated[c] = append(aggregated[c], communityLink{target: target, weight: targets[target]})
+		}
+	}
+	return aggregated
+}
+
+// labelPropagation returns the community of every vertex of links, numbered from 0.
+func labelPropagation(links [][]communityLink) []int {
+	labels := make([]int, len(links))
+	for v := range labels {
+		labels[v] = v
+	}
+	for iteration, changed := 0, true; changed && iteration < 100; iteration++ {
+		changed = false
+		for v := range links {
+			weights := make(map[int]float64)
+			for _, link := range links[v] {
+				weights[labels[link.target]] += link.weight
+			}
+			// ties keep the current label if possible, and pick the smallest label otherwise
+			best := labels[v]
+			for _, label := range sortedIntKeys(weights) {
+				if weights[label] > weights[best] {
+					best = label
+				}
+			}
+			if best != labels[v] {
+				labels[v] = best
+				changed = true
+			}
+		}
+	}
+	return renumber(labels)
+}
+
+// modularity computes the modularity of membership over links.
+func modularity(links [][]communityLink, membership []int) float64 {
+	inside := make(map[int]float64)
+	totals := make(map[int]float64)
+	total := 0.0
+	for v, vertexLinks := range links {
+		for _, link := range vertexLinks {
+			totals[membership[v]] += link.weight
+			total += link.weight
+			if membership[v] == membership[link.target] {
+				inside[membership[v]] += link.weight
+			}
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	q := 0.0
+	for community, weight := range totals {
+		q += inside[community]/total - (weight/total)*(weight/total)
+	}
+	return q
+}
+
+// renumber maps communities to consecutive numbers from 0, in order of appearance.
+func renumber(communities []int) []int {
+	numbers := make(map[int]int)
+	renumbered := make([]int, len(communities))
+	for v, community := range communities {
+		number, exists := numbers[community]
+		if !exists {
+			number = len(numbers)
+			numbers[community] = number
+		}
+		renumbered[v] = number
+	}
+	return renumbered
+}
+
+// groupMembers lists the names of the vertices of every community. Names are sorted, so numbering communities in
+// order of appearance sorts them by their first vertex.
+func groupMembers(names []string, membership []int) [][]string {
+	var communities [][]string
+	for v, community := range renumber(membership) {
+		if community == len(communities) {
+			communities = append(communities, nil)
+		}
+		communities[community] = append(communities[community], names[v])
+	}
+	return communities
+}
+
+func sortedIntKeys(values map[int]float64) []int {
+	keys := make([]int, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	return keys
+}
@@ -0,0 +1,236 @@
+package dot
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/christat/search"
+)
+
+// CompactGraph is a frozen, integer indexed representation of a Graph in compressed sparse row (CSR) form.
+// Vertices are identified by dense IDs in [0, Order()), assigned in alphabetical order of their names. The edges
+// leaving vertex v are stored in targets[offsets[v]:offsets[v+1]], with their costs at the same positions of weights.
+// Costs and heuristics are evaluated once, through Vertex.Cost and Vertex.Heuristic, when the CompactGraph is built.
+type CompactGraph struct {
+	Name string
+	Type string
+
+	names      []string
+	index      map[string]int
+	offsets    []int
+	targets    []int
+	weights    []float64
+	heuristics []float64
+
+	// defaultCost is the default cost of the original graph, returned by CompactVertex.Cost for missing edges.
+	defaultCost float64
+
+	// search.State views of the vertices, built on first use.
+	statesOnce sync.Once
+	states     []CompactVertex
+	neighbors  [][]search.State
+}
+
+// Compact builds the CompactGraph representation of g. Later modifications of g are not reflected in it.
+func (g *Graph) Compact() *CompactGraph {
+	return g.compact(
+		func(origin, target *Vertex) float64 { return origin.Cost(target) },
+		func(vertex *Vertex) float64 { return vertex.Heuristic() })
+}
+
+// topology builds the CompactGraph representation of g with zero costs and heuristics, for algorithms which only
+// depend on the structure of the graph.
+func (g *Graph) topology() *CompactGraph {
+	return g.compact(
+		func(origin, target *Vertex) float64 { return 0 },
+		func(vertex *Vertex) float64 { return 0 })
+}
+
+// compact builds the CompactGraph representation of g, evaluating costs and heuristics through the given functions.
+func (g *Graph) compact(cost func(origin, target *Vertex) float64, heuristic func(vertex *Vertex) float64) *CompactGraph {
+	names := make([]string, 0, len(g.vertexMap))
+	for name := range g.vertexMap {
+		names = append(names, name)
+	}
+	for origin, neighbors := range g.adjacencyMap {
+		if _, exists := g.vertexMap[origin]; !exists {
+			names = append(names, origin)
+		}
+		for _, neighbor := range neighbors {
+			if _, exists := g.vertexMap[neighbor.Name()]; !exists {
+				names = append(names, neighbor.Name())
+			}
+		}
+	}
+	sort.Strings(names)
+	names = uniqueSorted(names)
+
+	c := &CompactGraph{
+		Name:       g.Name,
+		Type:       g.Type,
+		names:      names,
+		index:      make(map[string]int, len(names)),
+		offsets:    make([]int, len(names)+1),
+		heuristics: make([]float64, len(names)),
+
+		defaultCost: g.DefaultCost(),
+	}
+	for id, name := range names {
+		c.index[name] = id
+	}
+
+	edgeCount := 0
+	for _, neighbors := range g.adjacencyMap {
+		edgeCount += len(neighbors)
+	}
+	c.targets = make([]int, 0, edgeCount)
+	c.weights = make([]float64, 0, edgeCount)
+	for id, name := range names {
+		vertex := g.vertex(name)
+		c.offsets[id] = len(c.targets)
+		c.heuristics[id] = heuristic(vertex)
+		for _, neighbor := range g.adjacencyMap[name] {
+			c.targets = append(c.targets, c.index[neighbor.Name()])
+			c.weights = append(c.weights, cost(vertex, g.vertex(neighbor.Name())))
+		}
+	}
+	c.offsets[len(names)] = len(c.targets)
+	return c
+}
+
+// Order returns the number of vertices of the graph.
+func (c *CompactGraph) Order() int {
+	return len(c.names)
+}
+
+// Size returns the number of (directed) edges of the graph. Undirected edges are counted once per direction.
+func (c *CompactGraph) Size() int {
+	return len(c.targets)
+}
+
+// ID returns the dense identifier of the vertex with the given name, and whether the vertex exists.
+func (c *CompactGraph) ID(name string) (int, bool) {
+	id, exists := c.index[name]
+	return id, exists
+}
+
+// VertexName returns the name of the vertex identified by id.
+func (c *CompactGraph) VertexName(id int) string {
+	return c.names[id]
+}
+
+// Names returns the names of all vertices, indexed by ID. The returned slice must not be modified.
+func (c *CompactGraph) Names() []string {
+	return c.names
+}
+
+// Neighbors returns the IDs of the vertices adjacent to id. The returned slice must not be modified.
+func (c *CompactGraph) Neighbors(id int) []int {
+	return c.targets[c.offsets[id]:c.offsets[id+1]]
+}
+
+// Weights returns the costs of the edges leaving id, in the same order as Neighbors(id).
+// The returned slice must not be modified.
+func (c *CompactGraph) Weights(id int) []float64 {
+	return c.weights[c.offsets[id]:c.offsets[id+1]]
+}
+
+// Heuristic returns the heuristic value of the vertex identified by id.
+func (c *CompactGraph) Heuristic(id int) float64 {
+	return c.heuristics[id]
+}
+
+// Vertex returns a search.State view of the vertex with the given name, or nil if it does not exist.
+func (c *CompactGraph) Vertex(name string) *CompactVertex {
+	id, exists := c.index[name]
+	if !exists {
+		return nil
+	}
+	c.buildStates()
+	return &c.states[id]
+}
+
+func (c *CompactGraph) buildStates() {
+	c.statesOnce.Do(func() {
+		c.states = make([]CompactVertex, len(c.names))
+		for id := range c.states {
+			c.states[id] = CompactVertex{id: id, graph: c}
+		}
+		c.neighbors = make([][]search.State, len(c.names))
+		for id := range c.neighbors {
+			targets := c.Neighbors(id)
+			c.neighbors[id] = make([]search.State, len(targets))
+			for i, target := range targets {
+				c.neighbors[id][i] = &c.states[target]
+			}
+		}
+	})
+}
+
+// CompactVertex is a vertex of a CompactGraph. Like Vertex, it implements search.State (and its weighted and
+// heuristic variants), but answers every query from the precomputed CSR arrays.
+type CompactVertex struct {
+	id    int
+	graph *CompactGraph
+}
+
+// ID returns the dense identifier of the vertex.
+func (v *CompactVertex) ID() int {
+	return v.id
+}
+
+// Name returns the unique identifier of the Vertex, i.e. its name.
+func (v *CompactVertex) Name() string {
+	return v.graph.names[v.id]
+}
+
+// Equals implements the search.State interface, comparing two vertices of the same CompactGraph.
+func (v *CompactVertex) Equals(other search.State) bool {
+	o, ok := other.(*CompactVertex)
+	return ok && o.graph == v.graph && o.id == v.id
+}
+
+// Neighbors returns the vertices adjacent to the caller. The returned slice must not be modified.
+func (v *CompactVertex) Neighbors() []search.State {
+	return v.graph.neighbors[v.id]
+}
+
+// Cost returns the precomputed cost of the edge from v to target. If several edges connect them, the cheapest
+// one is used; if there is none, the default cost of the original graph is returned.
+func (v *CompactVertex) Cost(target search.State) float64 {
+	t, ok := target.(*CompactVertex)
+	if !ok {
+		return v.graph.defaultCost
+	}
+	cost, found := v.graph.defaultCost, false
+	weights := v.graph.Weights(v.id)
+	for i, neighbor := range v.graph.Neighbors(v.id) {
+		if neighbor == t.id && (!found || weights[i] < cost) {
+			cost, found = weights[i], true
+		}
+	}
+	return cost
+}
+
+// Heuristic returns the precomputed heuristic value of the vertex.
+func (v *CompactVertex) Heuristic() float64 {
+	return v.graph.heuristics[v.id]
+}
+
+// vertex returns the Vertex instance of name, creating a detached one if it is only referenced by adjacency lists.
+func (g *Graph) vertex(name string) *Vertex {
+	if vertex, exists := g.vertexMap[name]; exists {
+		return vertex
+	}
+	return NewVertex(name, g)
+}
+
+func uniqueSorted(names []string) []string {
+	unique := names[:0]
+	for i, name := range names {
+		if i == 0 || name != names[i-1] {
+			unique = append(unique, name)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,143 @@
+package dot
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// StronglyConnectedComponents returns the strongly connected components of the graph, computed with Tarjan's
+// algorithm. Each component lists its vertices sorted alphabetically, and components are ordered topologically:
+// no edge leads from a component to an earlier one. In undirected graphs, components are the connected components.
+func (g *Graph) StronglyConnectedComponents() [][]string {
+	c := g.topology()
+	ids := c.stronglyConnected()
+	components := make([][]string, len(ids))
+	// Tarjan's algorithm finds components in reverse topological order
+	for i, component := range ids {
+		names := make([]string, len(component))
+		for j, id := range component {
+			names[j] = c.names[id]
+		}
+		sort.Strings(names)
+		components[len(ids)-1-i] = names
+	}
+	return components
+}
+
+// Condensation returns a new digraph with one vertex per strongly connected component of g, named "scc<i>" after
+// its position in StronglyConnectedComponents, and an edge between two components whenever an edge of g connects
+// their members. Vertices carry the attributes "members", the comma separated names of the members, and "size";
+// edges carry "edges", the number of edges of g they stand for. The result is acyclic.
+func (g *Graph) Condensation() *Graph {
+	components := g.StronglyConnectedComponents()
+	componentOf := make(map[string]string)
+	condensation := NewGraph()
+	condensation.Name = g.Name
+	condensation.Type = "digraph"
+	for i, members := range components {
+		name := "scc" + strconv.Itoa(i)
+		condensation.fetchOrCreateVertex(name)
+		condensation.vertexAttributes[name] = map[string]interface{}{
+			"members": strings.Join(members, ","),
+			"size":    len(members),
+		}
+		for _, member := range members {
+			componentOf[member] = name
+		}
+	}
+
+	for i := range components {
+		origin := "scc" + strconv.Itoa(i)
+		counts := make(map[string]int)
+		var targets []string
+		for _, member := range components[i] {
+			for _, neighbor := range g.adjacencyMap[member] {
+				target := componentOf[neighbor.Name()]
+				if target == origin {
+					continue
+				}
+				if counts[target] == 0 {
+					targets = append(targets, target)
+				}
+				counts[target]++
+			}
+		}
+		for _, target := range targets {
+			condensation.adjacencyMap[origin] = append(condensation.adjacencyMap[origin],
+				condensation.fetchOrCreateVertex(target))
+			condensation.SetEdgeAttributes(origin, target, true, map[string]interface{}{"edges": counts[target]})
+		}
+	}
+	return condensation
+}
+
+// stronglyConnected returns the strongly connected components of c in reverse topological order, as found by an
+// iterative version of Tarjan's algorithm.
+func (c *CompactGraph) stronglyConnected() [][]int {
+	order := c.Order()
+	index := make([]int, order)
+	lowLink := make([]int, order)
+	onStack := make([]bool, order)
+	for v := range index {
+		index[v] = -1
+	}
+	var stack []int
+	var components [][]int
+	counter := 0
+
+	type frame struct {
+		vertex int
+		edge   int
+	}
+	for root := 0; root < order; root++ {
+		if index[root] != -1 {
+			continue
+		}
+		calls := []frame{{vertex: root, edge: c.offsets[root]}}
+		index[root], lowLink[root] = counter, counter
+		counter++
+		stack = append(stack, root)
+		onStack[root] = true
+		for len(calls) > 0 {
+			top := &calls[len(calls)-1]
+			v := top.vertex
+			if top.edge < c.offsets[v+1] {
+				w := c.targets[top.edge]
+				top.edge++
+				if index[w] == -1 {
+					index[w], lowLink[w] = counter, counter
+					counter++
+					stack = append(stack, w)
+					onStack[w] = true
+					calls = append(calls, frame{vertex: w, edge: c.offsets[w]})
+				} else if onStack[w] && index[w] < lowLink[v] {
+					lowLink[v] = index[w]
+				}
+				continue
+			}
+			// every edge of v has been explored: pop it, closing its component if v is the root of one
+			calls = calls[:len(calls)-1]
+			if len(calls) > 0 {
+				parent := calls[len(calls)-1].vertex
+				if lowLink[v] < lowLink[parent] {
+					lowLink[parent] = lowLink[v]
+				}
+			}
+			if lowLink[v] == index[v] {
+				var component []int
+				for {
+					w := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[w] = false
+					component = append(component, w)
+					if w == v {
+						break
+					}
+				}
+				components = append(components, component)
+			}
+		}
+	}
+	return components
+}
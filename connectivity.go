@@ -0,0 +1,223 @@
+package dot
+
+import "sort"
+
+// Edge identifies an edge by the names of its endpoints. Edges reported for undirected analyses hold their endpoints
+// in alphabetical order.
+type Edge struct {
+	Origin string
+	Target string
+}
+
+// ConnectedComponents returns the connected components of the graph, ignoring the direction of edges (i.e. the
+// weakly connected components of a digraph). Each component lists its vertices sorted alphabetically, and components
+// are ordered by their first vertex.
+func (g *Graph) ConnectedComponents() [][]string {
+	view := g.undirectedView()
+	component := make([]int, len(view.names))
+	for v := range component {
+		component[v] = -1
+	}
+	var components [][]string
+	for root := range view.names {
+		if component[root] != -1 {
+			continue
+		}
+		id := len(components)
+		members := []string{view.names[root]}
+		component[root] = id
+		// vertices are visited in ID order and IDs are alphabetical, so members only need sorting at the end
+		for queue := []int{root}; len(queue) > 0; queue = queue[1:] {
+			for _, half := range view.adjacency[queue[0]] {
+				if component[half.target] == -1 {
+					component[half.target] = id
+					members = append(members, view.names[half.target])
+					queue = append(queue, half.target)
+				}
+			}
+		}
+		sort.Strings(members)
+		components = append(components, members)
+	}
+	return components
+}
+
+// ArticulationPoints returns the vertices whose removal increases the number of connected components of the graph,
+// sorted alphabetically. Like ConnectedComponents, it ignores the direction of edges.
+func (g *Graph) ArticulationPoints() []string {
+	view := g.undirectedView()
+	articulation, _ := view.cuts()
+	var points []string
+	for v, isPoint := range articulation {
+		if isPoint {
+			points = append(points, view.names[v])
+		}
+	}
+	return points
+}
+
+// Bridges returns the edges whose removal increases the number of connected components of the graph, sorted by
+// origin and target. Like ConnectedComponents, it ignores the direction of edges; the symmetric entries stored for
+// an undirected edge count as a single edge, while edges declared several times between the same vertices are
+// parallel and never bridges.
+func (g *Graph) Bridges() []Edge {
+	view := g.undirectedView()
+	_, bridges := view.cuts()
+	sort.Slice(bridges, func(i, j int) bool {
+		if bridges[i].Origin != bridges[j].Origin {
+			return bridges[i].Origin < bridges[j].Origin
+		}
+		return bridges[i].Target < bridges[j].Target
+	})
+	return bridges
+}
+
+// undirectedView is an undirected multigraph over the vertices of a Graph, with dense IDs assigned in alphabetical
+// order. Every undirected edge has an ID below edges and is stored once at each endpoint. Self loops, which never
+// affect connectivity, are only counted in loops.
+type undirectedView struct {
+	names     []string
+	adjacency [][]halfEdge
+	edges     int
+	loops     []int
+}
+
+type halfEdge struct {
+	target int
+	edge   int
+}
+
+// undirectedView builds the undirected view of g, ignoring the direction of edges. An edge declared with "--" is
+// stored by the parser as two opposite entries, which form a single edge of the view; any other entry is an edge
+// of its own. As undirected edges are recorded per pair of vertices (see IsUndirectedEdge), between such a pair
+// the entries present in both directions are taken as "--" edges and the rest as directed ones. The same holds for
+// self loops, whose "--" edges are stored as two entries of the same list.
+func (g *Graph) undirectedView() *undirectedView {
+	c := g.topology()
+	counts := make(map[[2]int]int)
+	for v := 0; v < c.Order(); v++ {
+		for _, w := range c.Neighbors(v) {
+			if v != w {
+				counts[[2]int{v, w}]++
+			}
+		}
+	}
+	view := &undirectedView{names: c.names, adjacency: make([][]halfEdge, c.Order()), loops: make([]int, c.Order())}
+	for v := 0; v < c.Order(); v++ {
+		for _, w := range c.Neighbors(v) {
+			if v == w {
+				view.loops[v]++
+			}
+		}
+		if view.loops[v] > 0 && g.IsUndirectedEdge(c.names[v], c.names[v]) {
+			// every pair of entries is a "--" loop, and an odd one out a "->" loop
+			view.loops[v] = (view.loops[v] + 1) / 2
+		}
+		for _, w := range uniqueInts(c.Neighbors(v)) {
+			forward, backward := counts[[2]int{v, w}], counts[[2]int{w, v}]
+			if v == w || (backward > 0 && w < v) {
+				// self loop, or pair already added from w
+				continue
+			}
+			multiplicity := forward + backward
+			if g.IsUndirectedEdge(c.names[v], c.names[w]) {
+				if forward < backward {
+					multiplicity -= forward
+				} else {
+					multiplicity -= backward
+				}
+			}
+			for i := 0; i < multiplicity; i++ {
+				view.adjacency[v] = append(view.adjacency[v], halfEdge{target: w, edge: view.edges})
+				view.adjacency[w] = append(view.adjacency[w], halfEdge{target: v, edge: view.edges})
+				view.edges++
+			}
+		}
+	}
+	return view
+}
+
+// cuts finds the articulation points and bridges of the view with an iterative version of Tarjan's low-link
+// depth first search.
+func (view *undirectedView) cuts() (articulation []bool, bridges []Edge) {
+	order := len(view.names)
+	discovery := make([]int, order)
+	low := make([]int, order)
+	articulation = make([]bool, order)
+	for v := range discovery {
+		discovery[v] = -1
+	}
+	type frame struct {
+		vertex     int
+		parentEdge int
+		next       int
+	}
+	time := 0
+	for root := 0; root < order; root++ {
+		if discovery[root] != -1 {
+			continue
+		}
+		discovery[root], low[root] = time, time
+		time++
+		children := 0
+		stack := []frame{{vertex: root, parentEdge: -1}}
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			v := top.vertex
+			if top.next < len(view.adjacency[v]) {
+				half := view.adjacency[v][top.next]
+				top.next++
+				// only the edge leading to v is skipped, so that parallel edges are seen as back edges
+				if half.edge == top.parentEdge {
+					continue
+				}
+				w := half.target
+				if discovery[w] == -1 {
+					discovery[w], low[w] = time, time
+					time++
+					if v == root {
+						children++
+					}
+					stack = append(stack, frame{vertex: w, parentEdge: half.edge})
+				} else if discovery[w] < low[v] {
+					low[v] = discovery[w]
+				}
+				continue
+			}
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				break
+			}
+			parent := stack[len(stack)-1].vertex
+			if low[v] < low[parent] {
+				low[parent] = low[v]
+			}
+			if low[v] > discovery[parent] {
+				bridges = append(bridges, Edge{Origin: view.names[parent], Target: view.names[v]})
+				if view.names[v] < view.names[parent] {
+					bridges[len(bridges)-1] = Edge{Origin: view.names[v], Target: view.names[parent]}
+				}
+			}
+			if parent != root && low[v] >= discovery[parent] {
+				articulation[parent] = true
+			}
+		}
+		articulation[root] = children > 1
+	}
+	return articulation, bridges
+}
+
+// uniqueInts returns the distinct values of ids, sorted.
+func uniqueInts(ids []int) []int {
+	unique := make([]int, len(ids))
+	copy(unique, ids)
+	sort.Ints(unique)
+	n := 0
+	for i, id := range unique {
+		if i == 0 || id != unique[n-1] {
+			unique[n] = id
+			n++
+		}
+	}
+	return unique[:n]
+}
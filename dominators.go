@@ -0,0 +1,200 @@
+package dot
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DominatorTree holds the dominators of the vertices reachable from a root vertex. A vertex a dominates b if every
+// path from the root to b goes through a; for post-dominators, paths lead from b to the root instead.
+type DominatorTree struct {
+	Root string
+	// Idom maps every vertex reachable from the root, except the root itself, to its immediate dominator: the
+	// closest of its strict dominators, which is its parent in the tree.
+	Idom map[string]string
+
+	graph    *Graph
+	children map[string][]string
+}
+
+// Dominators computes the dominator tree of the vertices reachable from entry, with the Lengauer-Tarjan algorithm.
+// Vertices which can't be reached from entry are not part of the tree.
+func (g *Graph) Dominators(entry string) (*DominatorTree, error) {
+	c := g.topology()
+	root, exists := c.index[entry]
+	if !exists {
+		return nil, fmt.Errorf("Dominators() from %v: vertex not found", entry)
+	}
+	successors := make([][]int, c.Order())
+	for v := range successors {
+		successors[v] = c.Neighbors(v)
+	}
+	return g.dominatorTree(c, root, successors), nil
+}
+
+// PostDominators computes the post-dominator tree of the vertices from which exit can be reached, i.e. the
+// dominator tree of the graph with reversed edges rooted at exit.
+func (g *Graph) PostDominators(exit string) (*DominatorTree, error) {
+	c := g.topology()
+	root, exists := c.index[exit]
+	if !exists {
+		return nil, fmt.Errorf("PostDominators() to %v: vertex not found", exit)
+	}
+	predecessors := make([][]int, c.Order())
+	for v := 0; v < c.Order(); v++ {
+		for _, w := range c.Neighbors(v) {
+			predecessors[w] = append(predecessors[w], v)
+		}
+	}
+	return g.dominatorTree(c, root, predecessors), nil
+}
+
+// dominatorTree runs Lengauer-Tarjan from root over the given successor lists. Vertices are numbered in depth first
+// order from 1, 0 meaning unvisited, and every slice below but numbers is indexed by these numbers.
+func (g *Graph) dominatorTree(c *CompactGraph, root int, successors [][]int) *DominatorTree {
+	numbers := make([]int, c.Order())
+	vertices := []int{-1}
+	parents := []int{0}
+	type entry struct {
+		vertex int
+		parent int
+	}
+	for stack := []entry{{vertex: root}}; len(stack) > 0; {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if numbers[top.vertex] != 0 {
+			continue
+		}
+		number := len(vertices)
+		numbers[top.vertex] = number
+		vertices = append(vertices, top.vertex)
+		parents = append(parents, top.parent)
+		// push successors in reverse, so that they are visited in adjacency order
+		for i := len(successors[top.vertex]) - 1; i >= 0; i-- {
+			if w := successors[top.vertex][i]; numbers[w] == 0 {
+				stack = append(stack, entry{vertex: w, parent: number})
+			}
+		}
+	}
+
+	count := len(vertices)
+	predecessors := make([][]int, count)
+	for v := 1; v < count; v++ {
+		for _, w := range successors[vertices[v]] {
+			predecessors[numbers[w]] = append(predecessors[numbers[w]], v)
+		}
+	}
+	semi := make([]int, count)
+	idom := make([]int, count)
+	ancestors := make([]int, count)
+	labels := make([]int, count)
+	buckets := make([][]int, count)
+	for v := range semi {
+		semi[v], labels[v] = v, v
+	}
+	// eval returns the vertex of minimal semidominator on the path from v to the root of its forest tree,
+	// compressing the path on the way
+	eval := func(v int) int {
+		if ancestors[v] == 0 {
+			return v
+		}
+		var path []int
+		for x := v; ancestors[ancestors[x]] != 0; x = ancestors[x] {
+			path = append(path, x)
+		}
+		for i := len(path) - 1; i >= 0; i-- {
+			x := path[i]
+			if a := ancestors[x]; semi[labels[a]] < semi[labels[x]] {
+				labels[x] = labels[a]
+			}
+			ancestors[x] = ancestors[ancestors[x]]
+		}
+		return labels[v]
+	}
+	for w := count - 1; w > 1; w-- {
+		for _, v := range predecessors[w] {
+			if u := eval(v); semi[u] < semi[w] {
+				semi[w] = semi[u]
+			}
+		}
+		buckets[semi[w]] = append(buckets[semi[w]], w)
+		parent := parents[w]
+		ancestors[w] = parent
+		for _, v := range buckets[parent] {
+			if u := eval(v); semi[u] < semi[v] {
+				idom[v] = u
+			} else {
+				idom[v] = parent
+			}
+		}
+		buckets[parent] = nil
+	}
+	for w := 2; w < count; w++ {
+		if idom[w] != semi[w] {
+			idom[w] = idom[idom[w]]
+		}
+	}
+
+	tree := &DominatorTree{
+		Root:     c.names[root],
+		Idom:     make(map[string]string, count-2),
+		graph:    g,
+		children: make(map[string][]string),
+	}
+	for w := 2; w < count; w++ {
+		vertex, dominator := c.names[vertices[w]], c.names[vertices[idom[w]]]
+		tree.Idom[vertex] = dominator
+		tree.children[dominator] = append(tree.children[dominator], vertex)
+	}
+	for _, children := range tree.children {
+		sort.Strings(children)
+	}
+	return tree
+}
+
+// Contains returns true if vertex is part of the tree.
+func (t *DominatorTree) Contains(vertex string) bool {
+	_, exists := t.Idom[vertex]
+	return exists || vertex == t.Root
+}
+
+// Children returns the vertices immediately dominated by vertex, sorted alphabetically.
+func (t *DominatorTree) Children(vertex string) []string {
+	children := make([]string, len(t.children[vertex]))
+	copy(children, t.children[vertex])
+	return children
+}
+
+// Dominates returns true if a dominates b. Every vertex of the tree dominates itself.
+func (t *DominatorTree) Dominates(a string, b string) bool {
+	if !t.Contains(a) || !t.Contains(b) {
+		return false
+	}
+	for current := b; ; current = t.Idom[current] {
+		if current == a {
+			return true
+		}
+		if current == t.Root {
+			return false
+		}
+	}
+}
+
+// Graph returns the tree as a new digraph, with an edge from every immediate dominator to the vertices it
+// immediately dominates. Vertices keep their attributes, and the graph keeps the name and configuration of the
+// original graph.
+func (t *DominatorTree) Graph() *Graph {
+	tree := t.graph.subgraph(t.Contains, func(string, string) bool { return false })
+	tree.Type = "digraph"
+	names := make([]string, 0, len(t.children))
+	for name := range t.children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, dominator := range names {
+		for _, vertex := range t.children[dominator] {
+			tree.adjacencyMap[dominator] = append(tree.adjacencyMap[dominator], tree.fetchOrCreateVertex(vertex))
+		}
+	}
+	return tree
+}
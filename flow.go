@@ -0,0 +1,362 @@
+package dot
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// flowEpsilon is the residual capacity below which an arc is considered saturated, absorbing rounding errors.
+const flowEpsilon = 1e-9
+
+// FlowResult holds the result of MaxFlow: the value of the maximum flow, the flow sent through every edge, and a
+// minimum cut separating the source from the sink.
+type FlowResult struct {
+	Value float64
+	// Cost is the total cost of the flow, i.e. the sum over all edges of their flow times their cost. It is only
+	// computed by MinCostMaxFlow.
+	Cost float64
+	// Flows maps every edge carrying flow to the amount it carries. Flow is never sent both ways between two vertices.
+	Flows map[Edge]float64
+	// SourceSide and SinkSide partition the vertices of the graph along a minimum cut, sorted alphabetically.
+	SourceSide []string
+	SinkSide   []string
+	// CutEdges lists the edges leading from SourceSide to SinkSide, whose capacities add up to Value.
+	CutEdges []Edge
+
+	graph *Graph
+}
+
+// MaxFlow computes a maximum flow from source to sink with Dinic's algorithm, reading the capacity of every edge
+// from the attribute capacityKey. Edges without the attribute have no capacity; an edge declared several times
+// between the same vertices holds a single set of attributes, so it counts once. Undirected edges can carry flow
+// in either direction, up to their capacity.
+// An error is returned if source or sink don't exist, or if a capacity is not a non-negative number.
+func (g *Graph) MaxFlow(source string, sink string, capacityKey string) (*FlowResult, error) {
+	c := g.topology()
+	sourceID, sinkID, err := c.flowEndpoints("MaxFlow", source, sink)
+	if err != nil {
+		return nil, err
+	}
+	network, edges, err := g.flowNetwork("MaxFlow", c, capacityKey, nil)
+	if err != nil {
+		return nil, err
+	}
+	return g.flowResult(c, network, edges, sourceID, network.dinic(sourceID, sinkID)), nil
+}
+
+// MinCostMaxFlow computes, among all maximum flows from source to sink, one whose total cost is minimal. Capacities
+// are read from the attribute capacityKey as in MaxFlow, and the configured costs are the costs per unit of flow of
+// every edge. Negative costs are supported, but a cycle of negative cost with free capacity yields a
+// *NegativeCycleError.
+func (g *Graph) MinCostMaxFlow(source string, sink string, capacityKey string) (*FlowResult, error) {
+	c := g.Compact()
+	sourceID, sinkID, err := c.flowEndpoints("MinCostMaxFlow", source, sink)
+	if err != nil {
+		return nil, err
+	}
+	network, edges, err := g.flowNetwork("MinCostMaxFlow", c, capacityKey, func(v int, w int) float64 {
+		cost := math.Inf(1)
+		weights := c.Weights(v)
+		for i, target := range c.Neighbors(v) {
+			if target == w && weights[i] < cost {
+				cost = weights[i]
+			}
+		}
+		return cost
+	})
+	if err != nil {
+		return nil, err
+	}
+	value, cost, cycle := network.successiveShortestPaths(sourceID, sinkID)
+	if cycle != nil {
+		names := make([]string, len(cycle))
+		for i, v := range cycle {
+			names[i] = c.names[v]
+		}
+		return nil, &NegativeCycleError{Cycle: names}
+	}
+	result := g.flowResult(c, network, edges, sourceID, value)
+	result.Cost = cost
+	return result, nil
+}
+
+// flowEndpoints validates the source and sink of the flow computation named function.
+func (c *CompactGraph) flowEndpoints(function string, source string, sink string) (int, int, error) {
+	sourceID, exists := c.index[source]
+	if !exists {
+		return 0, 0, fmt.Errorf("%v() from %v: vertex not found", function, source)
+	}
+	sinkID, exists := c.index[sink]
+	if !exists {
+		return 0, 0, fmt.Errorf("%v() to %v: vertex not found", function, sink)
+	}
+	if sourceID == sinkID {
+		return 0, 0, fmt.Errorf("%v() from %v to %v: source and sink must differ", function, source, sink)
+	}
+	return sourceID, sinkID, nil
+}
+
+// flowNetwork builds the residual network of g for the flow computation named function, with one arc per distinct edge holding the attribute capacityKey.
+// If cost is not nil, it gives the cost per unit of flow of the edge between two vertex IDs of c.
+// The edges are returned in the order of their arcs.
+func (g *Graph) flowNetwork(function string, c *CompactGraph, capacityKey string,
+	cost func(v int, w int) float64) (*flowNetwork, []Edge, error) {
+	network := newFlowNetwork(c.Order())
+	var edges []Edge
+	for v := 0; v < c.Order(); v++ {
+		for _, w := range uniqueInts(c.Neighbors(v)) {
+			if v == w {
+				continue
+			}
+			attributes := g.edgeAttributes[c.names[v]][c.names[w]]
+			if _, exists := attributes[capacityKey]; !exists {
+				continue
+			}
+			capacity, err := FloatAttribute(attributes, capacityKey)
+			if err != nil || capacity < 0 {
+				return nil, nil, fmt.Errorf("%v() of edge %v -> %v: capacity %v is not a non-negative number",
+					function, c.names[v], c.names[w], attributes[capacityKey])
+			}
+			arcCost := 0.0
+			if cost != nil {
+				arcCost = cost(v, w)
+			}
+			network.addArc(v, w, capacity, arcCost)
+			edges = append(edges, Edge{Origin: c.names[v], Target: c.names[w]})
+		}
+	}
+	return network, edges, nil
+}
+
+// flowResult reads the flow of every edge and the minimum cut off a network through which a maximum flow of the
+// given value has been sent.
+func (g *Graph) flowResult(c *CompactGraph, network *flowNetwork, edges []Edge, source int, value float64) *FlowResult {
+	result := &FlowResult{Value: value, Flows: make(map[Edge]float64), graph: g}
+	for i, edge := range edges {
+		flow := network.flow(2 * i)
+		reverse := Edge{Origin: edge.Target, Target: edge.Origin}
+		// cancel flow sent both ways between the same vertices
+		if opposite, exists := result.Flows[reverse]; exists {
+			delete(result.Flows, reverse)
+			flow -= opposite
+			if flow < 0 {
+				edge, flow = reverse, -flow
+			}
+		}
+		if flow > flowEpsilon {
+			result.Flows[edge] = flow
+		}
+	}
+
+	reachable := network.reachable(source)
+	for v, name := range c.names {
+		if reachable[v] {
+			result.SourceSide = append(result.SourceSide, name)
+		} else {
+			result.SinkSide = append(result.SinkSide, name)
+		}
+	}
+	for _, edge := range edges {
+		if reachable[c.index[edge.Origin]] && !reachable[c.index[edge.Target]] {
+			result.CutEdges = append(result.CutEdges, edge)
+		}
+	}
+	return result
+}
+
+// Store sets attribute on every edge of the graph MaxFlow was run on to the flow it carries, if any. Both
+// directions of an undirected edge share their attributes, so the attribute holds the flow in either direction.
+func (r *FlowResult) Store(attribute string) {
+	edges := make([]Edge, 0, len(r.Flows))
+	for edge := range r.Flows {
+		edges = append(edges, edge)
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].Origin != edges[j].Origin {
+			return edges[i].Origin < edges[j].Origin
+		}
+		return edges[i].Target < edges[j].Target
+	})
+	for _, edge := range edges {
+		r.graph.SetEdgeAttribute(edge.Origin, edge.Target, false, attribute, r.Flows[edge])
+	}
+}
+
+// flowNetwork is a residual network. Arc i and its reverse arc i^1 are stored next to each other.
+type flowNetwork struct {
+	arcs     [][]int
+	targets  []int
+	residual []float64
+	costs    []float64
+}
+
+func newFlowNetwork(order int) *flowNetwork {
+	return &flowNetwork{arcs: make([][]int, order)}
+}
+
+// addArc adds the arc origin -> target with the given capacity and cost per unit of flow, along with its reverse.
+func (n *flowNetwork) addArc(origin int, target int, capacity float64, cost float64) {
+	n.arcs[origin] = append(n.arcs[origin], len(n.targets))
+	n.targets = append(n.targets, target)
+	n.residual = append(n.residual, capacity)
+	n.costs = append(n.costs, cost)
+	n.arcs[target] = append(n.arcs[target], len(n.targets))
+	n.targets = append(n.targets, origin)
+	n.residual = append(n.residual, 0)
+	n.costs = append(n.costs, -cost)
+}
+
+// flow returns the flow sent through arc, which is the residual capacity of its reverse.
+func (n *flowNetwork) flow(arc int) float64 {
+	return n.residual[arc^1]
+}
+
+func (n *flowNetwork) push(arc int, amount float64) {
+	n.residual[arc] -= amount
+	n.residual[arc^1] += amount
+}
+
+// dinic sends a maximum flow from source to sink, returning its value.
+func (n *flowNetwork) dinic(source int, sink int) float64 {
+	total := 0.0
+	levels := make([]int, len(n.arcs))
+	next := make([]int, len(n.arcs))
+	for n.levelGraph(source, sink, levels) {
+		for v := range next {
+			next[v] = 0
+		}
+		for {
+			pushed := n.blockingFlow(source, sink, math.Inf(1), levels, next)
+			if pushed <= flowEpsilon {
+				break
+			}
+			total += pushed
+		}
+	}
+	return total
+}
+
+// levelGraph assigns every vertex its distance from source in the residual network, returning whether sink is
+// reachable.
+func (n *flowNetwork) levelGraph(source int, sink int, levels []int) bool {
+	for v := range levels {
+		levels[v] = -1
+	}
+	levels[source] = 0
+	for queue := []int{source}; len(queue) > 0; queue = queue[1:] {
+		v := queue[0]
+		for _, arc := range n.arcs[v] {
+			if w := n.targets[arc]; levels[w] == -1 && n.residual[arc] > flowEpsilon {
+				levels[w] = levels[v] + 1
+				queue = append(queue, w)
+			}
+		}
+	}
+	return levels[sink] != -1
+}
+
+// blockingFlow pushes up to limit units of flow from v to sink along arcs of the level graph. next holds the first
+// arc of every vertex which may still lead to sink.
+func (n *flowNetwork) blockingFlow(v int, sink int, limit float64, levels []int, next []int) float64 {
+	if v == sink {
+		return limit
+	}
+	for ; next[v] < len(n.arcs[v]); next[v]++ {
+		arc := n.arcs[v][next[v]]
+		w := n.targets[arc]
+		if levels[w] != levels[v]+1 || n.residual[arc] <= flowEpsilon {
+			continue
+		}
+		if pushed := n.blockingFlow(w, sink, math.Min(limit, n.residual[arc]), levels, next); pushed > flowEpsilon {
+			n.push(arc, pushed)
+			return pushed
+		}
+	}
+	return 0
+}
+
+// successiveShortestPaths sends a maximum flow from source to sink, always along the cheapest augmenting path found
+// by Bellman-Ford, returning its value and cost. If a cycle of negative cost is found in the residual network, the
+// vertices of the cycle are returned instead.
+func (n *flowNetwork) successiveShortestPaths(source int, sink int) (value float64, cost float64, cycle []int) {
+	order := len(n.arcs)
+	distances := make([]float64, order)
+	parents := make([]int, order)
+	for {
+		for v := range distances {
+			distances[v] = math.Inf(1)
+			parents[v] = -1
+		}
+		distances[source] = 0
+		relaxed := true
+		for pass := 0; pass < order && relaxed; pass++ {
+			relaxed = false
+			for v := 0; v < order; v++ {
+				if math.IsInf(distances[v], 1) {
+					continue
+				}
+				for _, arc := range n.arcs[v] {
+					w := n.targets[arc]
+					if n.residual[arc] > flowEpsilon && distances[v]+n.costs[arc] < distances[w] {
+						distances[w] = distances[v] + n.costs[arc]
+						parents[w] = arc
+						relaxed = true
+						if pass == order-1 {
+							return value, cost, n.cycleThrough(w, parents)
+						}
+					}
+				}
+			}
+		}
+		if math.IsInf(distances[sink], 1) {
+			return value, cost, nil
+		}
+
+		amount := math.Inf(1)
+		for v := sink; v != source; v = n.targets[parents[v]^1] {
+			amount = math.Min(amount, n.residual[parents[v]])
+		}
+		if math.IsInf(amount, 1) {
+			// a path of unbounded capacity can't be tracked
+			return value, cost, nil
+		}
+		for v := sink; v != source; v = n.targets[parents[v]^1] {
+			n.push(parents[v], amount)
+		}
+		value += amount
+		cost += amount * distances[sink]
+	}
+}
+
+// cycleThrough walks the arcs in parents back from vertex until it is certainly inside a cycle, and returns the
+// cycle in the direction of the arcs, starting and ending with the same vertex.
+func (n *flowNetwork) cycleThrough(vertex int, parents []int) []int {
+	for i := 0; i < len(n.arcs); i++ {
+		vertex = n.targets[parents[vertex]^1]
+	}
+	cycle := []int{vertex}
+	for current := n.targets[parents[vertex]^1]; current != vertex; current = n.targets[parents[current]^1] {
+		cycle = append(cycle, current)
+	}
+	cycle = append(cycle, vertex)
+	for i, j := 0, len(cycle)-1; i < j; i, j = i+1, j-1 {
+		cycle[i], cycle[j] = cycle[j], cycle[i]
+	}
+	return cycle
+}
+
+// reachable returns the vertices reachable from source through arcs with residual capacity.
+func (n *flowNetwork) reachable(source int) []bool {
+	reached := make([]bool, len(n.arcs))
+	reached[source] = true
+	for queue := []int{source}; len(queue) > 0; queue = queue[1:] {
+		for _, arc := range n.arcs[queue[0]] {
+			if w := n.targets[arc]; !reached[w] && n.residual[arc] > flowEpsilon {
+				reached[w] = true
+				queue = append(queue, w)
+			}
+		}
+	}
+	return reached
+}
@@ -3,6 +3,7 @@ package dot
 import (
 	"fmt"
 	"github.com/christat/search"
+	"sync"
 )
 
 // Graph contains the topology and attributes of a Graph, including name, type, and adjacency map and vertex/edge attributes.
@@ -16,6 +17,10 @@ type Graph struct {
 	CostFunc      func(origin, target *Vertex) float64
 	HeuristicFunc func(vertex *Vertex) float64
 
+	// StrictWeights makes Vertex.Cost and Vertex.Heuristic record an error, available through WeightErrors, whenever
+	// the attribute named by CostKey or HeuristicKey is missing or not numeric and the default value is used instead.
+	StrictWeights bool
+
 	// maps a vertex accessor per each unique vertex name. For internal use only
 	vertexMap map[string]*Vertex
 
@@ -28,6 +33,24 @@ type Graph struct {
 	// edgeAttributes stores, in a map for every vertex name, another map whose key is the target vertex name and
 	// the value is a third map of attributes in the form "name": "value".
 	edgeAttributes map[string]map[string]map[string]interface{}
+
+	// graphAttributes stores the attributes of the graph itself in the form "name": "value".
+	graphAttributes map[string]interface{}
+
+	// undirectedEdges marks, for every vertex name, the targets of the edges declared with "--". Both directions of
+	// such edges are stored in adjacencyMap, so a digraph can only tell them apart from "->" edges through this map.
+	undirectedEdges map[string]map[string]bool
+
+	// weightCache holds the numeric values of the attributes used as CostKey and HeuristicKey. See weights.go.
+	weightCache *weightCache
+	weightsOnce sync.Once
+
+	// costFallback and heuristicFallback override defaultCost and defaultHeuristic when set.
+	costFallback      *float64
+	heuristicFallback *float64
+
+	// profiles stores the named cost and heuristic profiles of the graph. See profile.go.
+	profiles map[string]Profile
 }
 
 // NewGraph creates and returns a pointer to a new Graph.
@@ -37,6 +60,8 @@ func NewGraph() (g *Graph) {
 	g.adjacencyMap = make(map[string][]search.State)
 	g.vertexAttributes = make(map[string]map[string]interface{})
 	g.edgeAttributes = make(map[string]map[string]map[string]interface{})
+	g.graphAttributes = make(map[string]interface{})
+	g.undirectedEdges = make(map[string]map[string]bool)
 	return g
 }
 
@@ -52,6 +77,8 @@ func BuildGraph(
 	g.adjacencyMap = adjacencyMap
 	g.vertexAttributes = vertexAttributes
 	g.edgeAttributes = edgeAttributes
+	g.undirectedEdges = make(map[string]map[string]bool)
+	g.InvalidateWeights()
 }
 
 // AdjacencyMap returns the adjacency map of the graph.
@@ -64,6 +91,12 @@ func (g *Graph) VertexMap() map[string]*Vertex {
 	return g.vertexMap
 }
 
+// IsUndirectedEdge returns true if the edge origin -> target was declared with "--", i.e. it can be traversed in
+// both directions even if the graph is a digraph.
+func (g *Graph) IsUndirectedEdge(origin string, target string) bool {
+	return g.undirectedEdges[origin][target]
+}
+
 // GetVertexAttributes allows obtaining the map of attributes for a given vertex.
 func (g *Graph) GetVertexAttributes(vertex string) (value map[string]interface{}, err error) {
 	attributes, exists := g.vertexAttributes[vertex]
@@ -73,6 +106,28 @@ func (g *Graph) GetVertexAttributes(vertex string) (value map[string]interface{}
 	return attributes, nil
 }
 
+// GetGraphAttributes returns the map of attributes of the graph itself.
+func (g *Graph) GetGraphAttributes() map[string]interface{} {
+	return g.graphAttributes
+}
+
+// SetGraphAttribute sets an attribute of the graph itself.
+func (g *Graph) SetGraphAttribute(attribute string, value interface{}) {
+	if g.graphAttributes == nil {
+		g.graphAttributes = make(map[string]interface{})
+	}
+	g.graphAttributes[attribute] = value
+}
+
+// GetGraphAttribute obtains the desired attribute of the graph itself. If not found, an error value is returned instead.
+func (g *Graph) GetGraphAttribute(attribute string) (interface{}, error) {
+	value, exists := g.graphAttributes[attribute]
+	if !exists {
+		return nil, fmt.Errorf("GetGraphAttribute() of graph %v: attribute %v not found", g.Name, attribute)
+	}
+	return value, nil
+}
+
 // SetVertexAttribute allows to add an attribute to an existing map of attributes for a given vertex.
 func (g *Graph) SetVertexAttribute(vertex string, attribute string, value interface{}) {
 	if g.vertexAttributes == nil {
@@ -83,6 +138,7 @@ func (g *Graph) SetVertexAttribute(vertex string, attribute string, value interf
 		g.vertexAttributes[vertex] = make(map[string]interface{})
 	}
 	g.vertexAttributes[vertex][attribute] = value
+	g.invalidateVertexWeights(attribute)
 }
 
 // GetVertexAttributes obtains the desired attribute of vertex. If not found, an error value is returned instead
@@ -110,6 +166,7 @@ func (g *Graph) SetEdgeAttributes(origin string, target string, isDirectional bo
 			g.edgeAttributes[origin] = make(map[string]map[string]interface{})
 		}
 		g.edgeAttributes[origin][target] = edgeAttributes
+		g.invalidateEdgeWeights("")
 
 		if !isDirectional {
 			g.SetEdgeAttributes(target, origin, true, edgeAttributes)
@@ -146,6 +203,7 @@ func (g *Graph) SetEdgeAttribute(origin string, target string, isUndirected bool
 		g.edgeAttributes[origin][target] = make(map[string]interface{})
 	}
 	g.edgeAttributes[origin][target][attribute] = value
+	g.invalidateEdgeWeights(attribute)
 
 	if isUndirected {
 		g.SetEdgeAttribute(target, origin, false, attribute, value)
@@ -171,6 +229,16 @@ func (g *Graph) GetEdgeAttribute(origin string, target string, attribute string)
 	return value, nil
 }
 
+// setUndirected marks both directions of the edge between origin and target as undirected.
+func (g *Graph) setUndirected(origin string, target string) {
+	for _, edge := range [][2]string{{origin, target}, {target, origin}} {
+		if g.undirectedEdges[edge[0]] == nil {
+			g.undirectedEdges[edge[0]] = make(map[string]bool)
+		}
+		g.undirectedEdges[edge[0]][edge[1]] = true
+	}
+}
+
 //
 func (g *Graph) fetchOrCreateVertex(name string) *Vertex {
 	vertex, exists := g.vertexMap[name]
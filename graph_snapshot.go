@@ -0,0 +1,455 @@
+package dot
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/christat/search"
+)
+
+// Snapshot is a read-only view of a Graph at a given point in time.
+// Its modification methods never alter the receiver: they return a new Snapshot which shares the adjacency and
+// attribute data of every untouched vertex with its parent, so deriving many variants of one base graph is cheap.
+// Use Graph() to obtain a mutable (and independent) Graph from a Snapshot.
+type Snapshot struct {
+	name          string
+	graphType     string
+	costKey       string
+	heuristicKey  string
+	costFunc      func(origin, target *Vertex) float64
+	heuristicFunc func(vertex *Vertex) float64
+
+	strictWeights     bool
+	costFallback      *float64
+	heuristicFallback *float64
+	profiles          map[string]Profile
+
+	// The indexes below are persistentMaps keyed by vertex name, so that a modification only copies the entries of
+	// the vertices it touches. The lists and maps they hold are shared between snapshots as well, and must never be
+	// modified in place.
+
+	// vertices holds the names of all vertices in the snapshot.
+	vertices persistentMap[struct{}]
+
+	// adjacencyMap stores the names of the adjacent vertices of each vertex.
+	adjacencyMap persistentMap[[]string]
+
+	// vertexAttributes, edgeAttributes and undirectedEdges mirror their Graph counterparts.
+	vertexAttributes persistentMap[map[string]interface{}]
+	edgeAttributes   persistentMap[map[string]map[string]interface{}]
+	graphAttributes  map[string]interface{}
+	undirectedEdges  persistentMap[map[string]bool]
+}
+
+// Snapshot returns a read-only view of the current state of the graph.
+// Later modifications of the graph are not reflected in the returned Snapshot.
+func (g *Graph) Snapshot() *Snapshot {
+	s := &Snapshot{
+		name:              g.Name,
+		graphType:         g.Type,
+		costKey:           g.CostKey,
+		heuristicKey:      g.HeuristicKey,
+		costFunc:          g.CostFunc,
+		heuristicFunc:     g.HeuristicFunc,
+		strictWeights:     g.StrictWeights,
+		costFallback:      g.costFallback,
+		heuristicFallback: g.heuristicFallback,
+		profiles:          make(map[string]Profile, len(g.profiles)),
+		graphAttributes:   copyAttributes(g.graphAttributes),
+	}
+	for name, profile := range g.profiles {
+		s.profiles[name] = profile
+	}
+	for name := range g.vertexMap {
+		s.vertices = s.vertices.with(name, struct{}{})
+	}
+	for origin, neighbors := range g.adjacencyMap {
+		s.vertices = s.vertices.with(origin, struct{}{})
+		names := make([]string, len(neighbors))
+		for i, neighbor := range neighbors {
+			names[i] = neighbor.Name()
+			s.vertices = s.vertices.with(names[i], struct{}{})
+		}
+		s.adjacencyMap = s.adjacencyMap.with(origin, names)
+	}
+	for vertex, attributes := range g.vertexAttributes {
+		s.vertexAttributes = s.vertexAttributes.with(vertex, copyAttributes(attributes))
+	}
+	for origin, targets := range g.edgeAttributes {
+		copied := make(map[string]map[string]interface{}, len(targets))
+		for target, attributes := range targets {
+			copied[target] = copyAttributes(attributes)
+		}
+		s.edgeAttributes = s.edgeAttributes.with(origin, copied)
+	}
+	for origin, targets := range g.undirectedEdges {
+		s.undirectedEdges = s.undirectedEdges.with(origin, copyEdgeFlags(targets))
+	}
+	return s
+}
+
+// Graph builds a new mutable Graph holding the contents of the snapshot. The resulting graph shares no data
+// with the snapshot, and its vertices belong to the new graph.
+func (s *Snapshot) Graph() *Graph {
+	g := NewGraph()
+	g.Name = s.name
+	g.Type = s.graphType
+	g.CostKey = s.costKey
+	g.HeuristicKey = s.heuristicKey
+	g.CostFunc = s.costFunc
+	g.HeuristicFunc = s.heuristicFunc
+	g.StrictWeights = s.strictWeights
+	g.costFallback = s.costFallback
+	g.heuristicFallback = s.heuristicFallback
+	for name, profile := range s.profiles {
+		g.SetProfile(name, profile)
+	}
+	g.graphAttributes = copyAttributes(s.graphAttributes)
+
+	s.vertices.each(func(name string, _ struct{}) {
+		g.fetchOrCreateVertex(name)
+	})
+	s.adjacencyMap.each(func(origin string, neighbors []string) {
+		states := make([]search.State, len(neighbors))
+		for i, neighbor := range neighbors {
+			states[i] = g.vertexMap[neighbor]
+		}
+		g.adjacencyMap[origin] = states
+	})
+	s.vertexAttributes.each(func(vertex string, attributes map[string]interface{}) {
+		g.vertexAttributes[vertex] = copyAttributes(attributes)
+	})
+	s.edgeAttributes.each(func(origin string, targets map[string]map[string]interface{}) {
+		g.edgeAttributes[origin] = make(map[string]map[string]interface{}, len(targets))
+		for target, attributes := range targets {
+			g.edgeAttributes[origin][target] = copyAttributes(attributes)
+		}
+	})
+	s.undirectedEdges.each(func(origin string, targets map[string]bool) {
+		g.undirectedEdges[origin] = copyEdgeFlags(targets)
+	})
+	return g
+}
+
+// Name returns the name of the snapshotted graph.
+func (s *Snapshot) Name() string {
+	return s.name
+}
+
+// Type returns the type of the snapshotted graph, i.e. "graph" or "digraph".
+func (s *Snapshot) Type() string {
+	return s.graphType
+}
+
+// Vertices returns the names of all vertices in the snapshot, sorted alphabetically.
+func (s *Snapshot) Vertices() []string {
+	names := make([]string, 0, s.vertices.len())
+	s.vertices.each(func(name string, _ struct{}) {
+		names = append(names, name)
+	})
+	sort.Strings(names)
+	return names
+}
+
+// HasVertex reports whether the snapshot contains a vertex with the given name.
+func (s *Snapshot) HasVertex(vertex string) bool {
+	_, exists := s.vertices.get(vertex)
+	return exists
+}
+
+// Neighbors returns the names of the vertices adjacent to vertex.
+func (s *Snapshot) Neighbors(vertex string) []string {
+	neighbors, _ := s.adjacencyMap.get(vertex)
+	names := make([]string, len(neighbors))
+	copy(names, neighbors)
+	return names
+}
+
+// GetGraphAttributes returns a copy of the attributes of the graph itself.
+func (s *Snapshot) GetGraphAttributes() map[string]interface{} {
+	return copyAttributes(s.graphAttributes)
+}
+
+// GetGraphAttribute obtains the desired attribute of the graph itself.
+func (s *Snapshot) GetGraphAttribute(attribute string) (interface{}, error) {
+	value, exists := s.graphAttributes[attribute]
+	if !exists {
+		return nil, fmt.Errorf("GetGraphAttribute() of graph %v: attribute %v not found", s.name, attribute)
+	}
+	return value, nil
+}
+
+// GetVertexAttributes returns a copy of the map of attributes for a given vertex.
+func (s *Snapshot) GetVertexAttributes(vertex string) (map[string]interface{}, error) {
+	attributes, exists := s.vertexAttributes.get(vertex)
+	if !exists {
+		return nil, fmt.Errorf("GetVertexAttributes() of vertex %v: vertex has no attributes", vertex)
+	}
+	return copyAttributes(attributes), nil
+}
+
+// GetVertexAttribute obtains the desired attribute of vertex. If not found, an error value is returned instead.
+func (s *Snapshot) GetVertexAttribute(vertex string, attribute string) (interface{}, error) {
+	attributes, exists := s.vertexAttributes.get(vertex)
+	if !exists {
+		return nil, fmt.Errorf("GetVertexAttribute() of vertex %v: vertex has no attributes", vertex)
+	}
+	value, exists := attributes[attribute]
+	if !exists {
+		return nil, fmt.Errorf("GetVertexAttribute() of vertex %v: attribute %v not found", vertex, attribute)
+	}
+	return value, nil
+}
+
+// GetEdgeAttributes returns a copy of the attributes of the edge origin -> target.
+func (s *Snapshot) GetEdgeAttributes(origin string, target string) (map[string]interface{}, error) {
+	targets, exists := s.edgeAttributes.get(origin)
+	if !exists {
+		return nil, fmt.Errorf("GetEdgeAttributes() of edge <%v> : failed to find origin", origin)
+	}
+	attributes, exists := targets[target]
+	if !exists {
+		return nil, fmt.Errorf("GetEdgeAttributes() of edges %v -> %v : failed to find connection", origin, target)
+	}
+	return copyAttributes(attributes), nil
+}
+
+// GetEdgeAttribute obtains the desired attribute of the edge origin -> target.
+func (s *Snapshot) GetEdgeAttribute(origin string, target string, attribute string) (interface{}, error) {
+	targets, exists := s.edgeAttributes.get(origin)
+	if !exists {
+		return nil, fmt.Errorf("GetEdgeAttribute() of edge <%v> : failed to find origin in map", origin)
+	}
+	attributes, exists := targets[target]
+	if !exists {
+		return nil, fmt.Errorf("GetEdgeAttribute() of edges %v -> %v : failed to find connection to target in map",
+			origin, target)
+	}
+	value, exists := attributes[attribute]
+	if !exists {
+		return nil, fmt.Errorf("GetEdgeAttribute() of edges %v -> %v, attribute %v : failed to find attribute",
+			origin, target, attribute)
+	}
+	return value, nil
+}
+
+// IsUndirectedEdge returns true if the edge origin -> target is undirected. See Graph.IsUndirectedEdge.
+func (s *Snapshot) IsUndirectedEdge(origin string, target string) bool {
+	targets, _ := s.undirectedEdges.get(origin)
+	return targets[target]
+}
+
+// WithVertex returns a new snapshot which additionally contains the given vertex.
+func (s *Snapshot) WithVertex(vertex string) *Snapshot {
+	if s.HasVertex(vertex) {
+		return s
+	}
+	next := *s
+	next.vertices = s.vertices.with(vertex, struct{}{})
+	return &next
+}
+
+// WithoutVertex returns a new snapshot in which the given vertex, its attributes and all edges
+// from or to it have been removed. Every vertex is visited to find the edges leading to the removed one, but only
+// the entries of their origins are copied.
+func (s *Snapshot) WithoutVertex(vertex string) *Snapshot {
+	if !s.HasVertex(vertex) {
+		return s
+	}
+	next := *s
+	next.vertices = s.vertices.without(vertex)
+	next.vertexAttributes = s.vertexAttributes.without(vertex)
+
+	next.adjacencyMap = s.adjacencyMap.without(vertex)
+	s.adjacencyMap.each(func(origin string, neighbors []string) {
+		if filtered := withoutName(neighbors, vertex); origin != vertex && len(filtered) != len(neighbors) {
+			next.adjacencyMap = next.adjacencyMap.with(origin, filtered)
+		}
+	})
+
+	next.edgeAttributes = s.edgeAttributes.without(vertex)
+	s.edgeAttributes.each(func(origin string, targets map[string]map[string]interface{}) {
+		if _, exists := targets[vertex]; origin != vertex && exists {
+			targets = copyEdgeTargets(targets)
+			delete(targets, vertex)
+			next.edgeAttributes = next.edgeAttributes.with(origin, targets)
+		}
+	})
+
+	next.undirectedEdges = s.undirectedEdges.without(vertex)
+	s.undirectedEdges.each(func(origin string, targets map[string]bool) {
+		if origin != vertex && targets[vertex] {
+			next.setUndirected(origin, vertex, false)
+		}
+	})
+	return &next
+}
+
+// WithEdge returns a new snapshot which additionally contains the edge origin -> target.
+// If isDirectional is false, the edge target -> origin is added as well. Missing vertices are created.
+func (s *Snapshot) WithEdge(origin string, target string, isDirectional bool) *Snapshot {
+	next := s.WithVertex(origin).WithVertex(target)
+	if next == s {
+		copied := *s
+		next = &copied
+	}
+	next.addNeighbor(origin, target)
+	if !isDirectional {
+		next.addNeighbor(target, origin)
+		next.setUndirected(origin, target, true)
+		next.setUndirected(target, origin, true)
+	}
+	return next
+}
+
+// WithoutEdge returns a new snapshot in which the edge origin -> target and its attributes have been removed.
+// If isDirectional is false, the edge target -> origin is removed as well.
+func (s *Snapshot) WithoutEdge(origin string, target string, isDirectional bool) *Snapshot {
+	next := *s
+	next.removeEdge(origin, target)
+	if !isDirectional {
+		next.removeEdge(target, origin)
+	}
+	return &next
+}
+
+// WithGraphAttribute returns a new snapshot in which the given attribute of the graph itself is set to value.
+func (s *Snapshot) WithGraphAttribute(attribute string, value interface{}) *Snapshot {
+	next := *s
+	next.graphAttributes = copyAttributes(s.graphAttributes)
+	next.graphAttributes[attribute] = value
+	return &next
+}
+
+// WithVertexAttribute returns a new snapshot in which the given attribute of vertex is set to value.
+func (s *Snapshot) WithVertexAttribute(vertex string, attribute string, value interface{}) *Snapshot {
+	next := s.WithVertex(vertex)
+	if next == s {
+		copied := *s
+		next = &copied
+	}
+	attributes, _ := next.vertexAttributes.get(vertex)
+	attributes = copyAttributes(attributes)
+	attributes[attribute] = value
+	next.vertexAttributes = next.vertexAttributes.with(vertex, attributes)
+	return next
+}
+
+// WithEdgeAttribute returns a new snapshot in which the given attribute of the edge origin -> target is set to value.
+// If isDirectional is false, the attribute is set for target -> origin as well.
+func (s *Snapshot) WithEdgeAttribute(origin string, target string, isDirectional bool, attribute string,
+	value interface{}) *Snapshot {
+	next := *s
+	next.setEdgeAttribute(origin, target, attribute, value)
+	if !isDirectional {
+		next.setEdgeAttribute(target, origin, attribute, value)
+	}
+	return &next
+}
+
+// The helpers below modify the indexes of a snapshot which has just been copied from its parent, replacing the
+// entries they touch.
+
+// addNeighbor appends target to the adjacent vertices of origin.
+func (s *Snapshot) addNeighbor(origin string, target string) {
+	neighbors, _ := s.adjacencyMap.get(origin)
+	s.adjacencyMap = s.adjacencyMap.with(origin, withName(neighbors, target))
+}
+
+// removeEdge drops origin -> target, along with its attributes and undirected flag.
+func (s *Snapshot) removeEdge(origin string, target string) {
+	if neighbors, exists := s.adjacencyMap.get(origin); exists {
+		if filtered := withoutName(neighbors, target); len(filtered) != len(neighbors) {
+			s.adjacencyMap = s.adjacencyMap.with(origin, filtered)
+		}
+	}
+	if targets, exists := s.edgeAttributes.get(origin); exists {
+		if _, exists = targets[target]; exists {
+			targets = copyEdgeTargets(targets)
+			delete(targets, target)
+			s.edgeAttributes = s.edgeAttributes.with(origin, targets)
+		}
+	}
+	if s.IsUndirectedEdge(origin, target) {
+		s.setUndirected(origin, target, false)
+	}
+}
+
+// setUndirected flags or unflags origin -> target as undirected.
+func (s *Snapshot) setUndirected(origin string, target string, undirected bool) {
+	targets, _ := s.undirectedEdges.get(origin)
+	targets = copyEdgeFlags(targets)
+	if undirected {
+		targets[target] = true
+	} else {
+		delete(targets, target)
+	}
+	s.undirectedEdges = s.undirectedEdges.with(origin, targets)
+}
+
+// setEdgeAttribute sets an attribute of origin -> target.
+func (s *Snapshot) setEdgeAttribute(origin string, target string, attribute string, value interface{}) {
+	targets, _ := s.edgeAttributes.get(origin)
+	targets = copyEdgeTargets(targets)
+	attributes := copyAttributes(targets[target])
+	attributes[attribute] = value
+	targets[target] = attributes
+	s.edgeAttributes = s.edgeAttributes.with(origin, targets)
+}
+
+func copyAttributes(attributes map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(attributes)+1)
+	for key, value := range attributes {
+		copied[key] = value
+	}
+	return copied
+}
+
+func copyAttributesIndex(index map[string]map[string]interface{}) map[string]map[string]interface{} {
+	copied := make(map[string]map[string]interface{}, len(index)+1)
+	for key, attributes := range index {
+		copied[key] = attributes
+	}
+	return copied
+}
+
+func copyEdgeTargets(targets map[string]map[string]interface{}) map[string]map[string]interface{} {
+	return copyAttributesIndex(targets)
+}
+
+func copyEdgeFlags(targets map[string]bool) map[string]bool {
+	copied := make(map[string]bool, len(targets)+1)
+	for key, value := range targets {
+		copied[key] = value
+	}
+	return copied
+}
+
+// withName returns a new list holding names plus name. The original list is left untouched.
+func withName(names []string, name string) []string {
+	extended := make([]string, len(names), len(names)+1)
+	copy(extended, names)
+	return append(extended, name)
+}
+
+// withoutName returns names without any occurrence of name. The original list is returned if name is absent,
+// so that it keeps being shared.
+func withoutName(names []string, name string) []string {
+	found := false
+	for _, n := range names {
+		if n == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return names
+	}
+	filtered := make([]string, 0, len(names))
+	for _, n := range names {
+		if n != name {
+			filtered = append(filtered, n)
+		}
+	}
+	return filtered
+}
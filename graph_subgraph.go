@@ -0,0 +1,108 @@
+package dot
+
+import "github.com/christat/search"
+
+// Clone returns a deep copy of the graph. Vertices of the copy belong to the new graph, and attribute maps
+// are copied so that modifying either graph never affects the other.
+func (g *Graph) Clone() *Graph {
+	return g.subgraph(
+		func(string) bool { return true },
+		func(string, string) bool { return true })
+}
+
+// InducedSubgraph returns a new graph holding the given vertices and every edge of g connecting two of them.
+// Names which do not belong to g are ignored.
+func (g *Graph) InducedSubgraph(vertexNames []string) *Graph {
+	selected := make(map[string]bool, len(vertexNames))
+	for _, name := range vertexNames {
+		selected[name] = true
+	}
+	return g.subgraph(
+		func(vertex string) bool { return selected[vertex] },
+		func(origin, target string) bool { return selected[origin] && selected[target] })
+}
+
+// EdgeSubgraph returns a new graph holding the edges of g for which predicate returns true, along with the
+// vertices they connect. The attributes passed to predicate are nil if the edge has none, and must not be modified.
+func (g *Graph) EdgeSubgraph(predicate func(origin, target string, attributes map[string]interface{}) bool) *Graph {
+	selectedEdges := make(map[string]map[string]bool)
+	selectedVertices := make(map[string]bool)
+	for origin, neighbors := range g.adjacencyMap {
+		for _, neighbor := range neighbors {
+			target := neighbor.Name()
+			if predicate(origin, target, g.edgeAttributes[origin][target]) {
+				if selectedEdges[origin] == nil {
+					selectedEdges[origin] = make(map[string]bool)
+				}
+				selectedEdges[origin][target] = true
+				selectedVertices[origin] = true
+				selectedVertices[target] = true
+			}
+		}
+	}
+	return g.subgraph(
+		func(vertex string) bool { return selectedVertices[vertex] },
+		func(origin, target string) bool { return selectedEdges[origin][target] })
+}
+
+// subgraph copies the vertices and edges of g accepted by keepVertex and keepEdge into a new graph.
+// keepEdge is only consulted for edges whose endpoints are both kept.
+func (g *Graph) subgraph(keepVertex func(vertex string) bool, keepEdge func(origin, target string) bool) *Graph {
+	sub := NewGraph()
+	sub.Name = g.Name
+	sub.Type = g.Type
+	sub.CostKey = g.CostKey
+	sub.HeuristicKey = g.HeuristicKey
+	sub.CostFunc = g.CostFunc
+	sub.HeuristicFunc = g.HeuristicFunc
+	sub.StrictWeights = g.StrictWeights
+	sub.costFallback = g.costFallback
+	sub.heuristicFallback = g.heuristicFallback
+	sub.graphAttributes = copyAttributes(g.graphAttributes)
+	for name, profile := range g.profiles {
+		sub.SetProfile(name, profile)
+	}
+
+	for name := range g.vertexMap {
+		if keepVertex(name) {
+			sub.fetchOrCreateVertex(name)
+		}
+	}
+	for origin, neighbors := range g.adjacencyMap {
+		if !keepVertex(origin) {
+			continue
+		}
+		sub.fetchOrCreateVertex(origin)
+		kept := make([]search.State, 0, len(neighbors))
+		for _, neighbor := range neighbors {
+			target := neighbor.Name()
+			if keepVertex(target) && keepEdge(origin, target) {
+				kept = append(kept, sub.fetchOrCreateVertex(target))
+				if g.undirectedEdges[origin][target] && keepEdge(target, origin) {
+					sub.setUndirected(origin, target)
+				}
+			}
+		}
+		sub.adjacencyMap[origin] = kept
+	}
+
+	for vertex, attributes := range g.vertexAttributes {
+		if keepVertex(vertex) {
+			sub.vertexAttributes[vertex] = copyAttributes(attributes)
+		}
+	}
+	for origin, targets := range g.edgeAttributes {
+		if !keepVertex(origin) {
+			continue
+		}
+		for target, attributes := range targets {
+			if keepVertex(target) && keepEdge(origin, target) {
+				if sub.edgeAttributes[origin] == nil {
+					sub.edgeAttributes[origin] = make(map[string]map[string]interface{})
+				}
+				sub.edgeAttributes[origin][target] = copyAttributes(attributes)
+			}
+		}
+	}
+	return sub
+}
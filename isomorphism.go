@@ -0,0 +1,295 @@
+package dot
+
+import (
+	"reflect"
+	"sort"
+)
+
+// MatchOptions configures Isomorphic and SubgraphMatches.
+type MatchOptions struct {
+	// VertexAttributes lists the vertex attributes whose values must be equal for two vertices to match. An
+	// attribute missing from both vertices is considered equal.
+	VertexAttributes []string
+	// EdgeAttributes lists the edge attributes whose values must be equal for two edges to match.
+	EdgeAttributes []string
+	// Induced makes SubgraphMatches require that two matched vertices are adjacent in the graph only if they are in
+	// the pattern. By default, the graph may hold extra edges between matched vertices.
+	Induced bool
+	// Limit, if positive, stops SubgraphMatches once that many matches have been found.
+	Limit int
+}
+
+// Isomorphic checks whether g and other have the same structure regardless of vertex names, using a VF2 state space
+// search. Edges are compared along with their direction and multiplicity, and both graphs must be of the same Type.
+// If they are isomorphic, the returned map sends every vertex of g to its counterpart in other.
+// Only VertexAttributes and EdgeAttributes are read from opts.
+func (g *Graph) Isomorphic(other *Graph, opts MatchOptions) (map[string]string, bool) {
+	if g.Type != other.Type {
+		return nil, false
+	}
+	pattern, target := g.matchGraph(), other.matchGraph()
+	if len(pattern.names) != len(target.names) || pattern.size != target.size {
+		return nil, false
+	}
+	opts.Induced, opts.Limit = true, 1
+	matches := newMatcher(g, other, pattern, target, opts, true).run()
+	if len(matches) == 0 {
+		return nil, false
+	}
+	return matches[0], true
+}
+
+// SubgraphMatches finds the occurrences of pattern in g with a VF2 state space search: every returned map sends
+// the vertices of pattern to distinct vertices of g, such that every edge of pattern exists in g between the
+// vertices it is sent to, at least as many times. Both graphs must be of the same Type. Symmetric patterns match
+// the same vertices of g in several ways, each of which is returned. Matches are ordered deterministically.
+func (g *Graph) SubgraphMatches(pattern *Graph, opts MatchOptions) []map[string]string {
+	if g.Type != pattern.Type {
+		return nil
+	}
+	return newMatcher(pattern, g, pattern.matchGraph(), g.matchGraph(), opts, false).run()
+}
+
+// matchGraph holds the adjacency of a graph as edge counts between dense vertex IDs.
+type matchGraph struct {
+	names     []string
+	out       []map[int]int
+	in        []map[int]int
+	neighbors [][]int
+	size      int
+}
+
+func (g *Graph) matchGraph() *matchGraph {
+	c := g.topology()
+	m := &matchGraph{
+		names:     c.names,
+		out:       make([]map[int]int, c.Order()),
+		in:        make([]map[int]int, c.Order()),
+		neighbors: make([][]int, c.Order()),
+		size:      c.Size(),
+	}
+	for v := range m.out {
+		m.out[v], m.in[v] = make(map[int]int), make(map[int]int)
+	}
+	for v := 0; v < c.Order(); v++ {
+		for _, w := range c.Neighbors(v) {
+			m.out[v][w]++
+			m.in[w][v]++
+		}
+	}
+	for v := range m.neighbors {
+		for w := range m.out[v] {
+			m.neighbors[v] = append(m.neighbors[v], w)
+		}
+		for w := range m.in[v] {
+			if _, exists := m.out[v][w]; !exists {
+				m.neighbors[v] = append(m.neighbors[v], w)
+			}
+		}
+		sort.Ints(m.neighbors[v])
+	}
+	return m
+}
+
+// matcher maps the vertices of pattern onto the vertices of target, extending a partial mapping one pattern
+// vertex at a time in a fixed order.
+type matcher struct {
+	patternGraph, targetGraph *Graph
+	pattern, target           *matchGraph
+	opts                      MatchOptions
+	exact                     bool
+
+	order   []int
+	mapping []int
+	// reverse holds, for every target vertex, the pattern vertex mapped to it or -1
+	reverse []int
+	matches []map[string]string
+}
+
+func newMatcher(patternGraph *Graph, targetGraph *Graph, pattern *matchGraph, target *matchGraph, opts MatchOptions,
+	exact bool) *matcher {
+	m := &matcher{
+		patternGraph: patternGraph,
+		targetGraph:  targetGraph,
+		pattern:      pattern,
+		target:       target,
+		opts:         opts,
+		exact:        exact,
+		mapping:      make([]int, len(pattern.names)),
+		reverse:      make([]int, len(target.names)),
+	}
+	for p := range m.mapping {
+		m.mapping[p] = -1
+	}
+	for t := range m.reverse {
+		m.reverse[t] = -1
+	}
+	m.order = m.matchingOrder()
+	return m
+}
+
+// matchingOrder starts with the pattern vertex of highest degree, then repeatedly picks the vertex with the most
+// already ordered neighbors, so that candidates are drawn from the neighbors of mapped vertices whenever possible.
+func (m *matcher) matchingOrder() []int {
+	count := len(m.pattern.names)
+	ordered := make([]bool, count)
+	links := make([]int, count)
+	order := make([]int, 0, count)
+	for len(order) < count {
+		best := -1
+		for p := 0; p < count; p++ {
+			if ordered[p] {
+				continue
+			}
+			if best == -1 || links[p] > links[best] ||
+				(links[p] == links[best] && len(m.pattern.neighbors[p]) > len(m.pattern.neighbors[best])) {
+				best = p
+			}
+		}
+		ordered[best] = true
+		order = append(order, best)
+		for _, q := range m.pattern.neighbors[best] {
+			links[q]++
+		}
+	}
+	return order
+}
+
+func (m *matcher) run() []map[string]string {
+	if len(m.pattern.names) <= len(m.target.names) {
+		m.extend(0)
+	}
+	return m.matches
+}
+
+// extend maps the pattern vertex at position depth of the order, backtracking over every feasible candidate. It
+// returns false once the limit of matches has been reached.
+func (m *matcher) extend(depth int) bool {
+	if depth == len(m.order) {
+		match := make(map[string]string, len(m.mapping))
+		for p, t := range m.mapping {
+			match[m.pattern.names[p]] = m.target.names[t]
+		}
+		m.matches = append(m.matches, match)
+		return m.opts.Limit <= 0 || len(m.matches) < m.opts.Limit
+	}
+	p := m.order[depth]
+	for _, t := range m.candidates(p) {
+		if m.reverse[t] != -1 || !m.feasible(p, t) {
+			continue
+		}
+		m.mapping[p], m.reverse[t] = t, p
+		proceed := m.extend(depth + 1)
+		m.mapping[p], m.reverse[t] = -1, -1
+		if !proceed {
+			return false
+		}
+	}
+	return true
+}
+
+// candidates returns the target vertices p may be mapped to: the neighbors of the image of a mapped neighbor of p
+// if there is one, or every target vertex otherwise.
+func (m *matcher) candidates(p int) []int {
+	for _, q := range m.pattern.neighbors[p] {
+		if m.mapping[q] != -1 {
+			return m.target.neighbors[m.mapping[q]]
+		}
+	}
+	all := make([]int, len(m.target.names))
+	for t := range all {
+		all[t] = t
+	}
+	return all
+}
+
+// feasible checks whether mapping p to t is consistent with the current partial mapping: edges between p and
+// mapped vertices (and self loops) must have matching counts in target, and the unmapped neighbors of p must be
+// no more (exactly as many, for isomorphism) than those of t.
+func (m *matcher) feasible(p int, t int) bool {
+	if !m.compatible(len(m.pattern.out[p]), len(m.target.out[t])) ||
+		!m.compatible(len(m.pattern.in[p]), len(m.target.in[t])) {
+		return false
+	}
+	if !m.sameVertexAttributes(p, t) {
+		return false
+	}
+	if !m.compatibleEdges(p, p, t, t) {
+		return false
+	}
+	for _, q := range m.pattern.neighbors[p] {
+		if u := m.mapping[q]; u != -1 {
+			if !m.compatibleEdges(p, q, t, u) || !m.compatibleEdges(q, p, u, t) {
+				return false
+			}
+		}
+	}
+	if m.exact || m.opts.Induced {
+		// target edges between t and mapped vertices must also exist in pattern
+		for _, u := range m.target.neighbors[t] {
+			if q := m.reverse[u]; q != -1 && m.pattern.out[p][q] == 0 && m.pattern.in[p][q] == 0 {
+				return false
+			}
+		}
+	}
+
+	unmappedPattern, unmappedTarget := 0, 0
+	for _, q := range m.pattern.neighbors[p] {
+		if m.mapping[q] == -1 && q != p {
+			unmappedPattern++
+		}
+	}
+	for _, u := range m.target.neighbors[t] {
+		if m.reverse[u] == -1 && u != t {
+			unmappedTarget++
+		}
+	}
+	return m.compatible(unmappedPattern, unmappedTarget)
+}
+
+// compatible compares a pattern quantity with the target one: they must be equal for isomorphism, and the target
+// one at least as large for subgraph matching.
+func (m *matcher) compatible(pattern int, target int) bool {
+	if m.exact {
+		return pattern == target
+	}
+	return pattern <= target
+}
+
+// compatibleEdges compares the edges p -> q of pattern with the edges t -> u of target.
+func (m *matcher) compatibleEdges(p int, q int, t int, u int) bool {
+	patternCount, targetCount := m.pattern.out[p][q], m.target.out[t][u]
+	if m.exact || m.opts.Induced {
+		if patternCount != targetCount {
+			return false
+		}
+	} else if patternCount > targetCount {
+		return false
+	}
+	if patternCount == 0 || len(m.opts.EdgeAttributes) == 0 {
+		return true
+	}
+	patternAttributes := m.patternGraph.edgeAttributes[m.pattern.names[p]][m.pattern.names[q]]
+	targetAttributes := m.targetGraph.edgeAttributes[m.target.names[t]][m.target.names[u]]
+	return sameAttributes(patternAttributes, targetAttributes, m.opts.EdgeAttributes)
+}
+
+func (m *matcher) sameVertexAttributes(p int, t int) bool {
+	if len(m.opts.VertexAttributes) == 0 {
+		return true
+	}
+	return sameAttributes(m.patternGraph.vertexAttributes[m.pattern.names[p]],
+		m.targetGraph.vertexAttributes[m.target.names[t]], m.opts.VertexAttributes)
+}
+
+// sameAttributes checks that both attribute maps hold equal values (or no value) for every key.
+func sameAttributes(a map[string]interface{}, b map[string]interface{}, keys []string) bool {
+	for _, key := range keys {
+		valueA, existsA := a[key]
+		valueB, existsB := b[key]
+		if existsA != existsB || !reflect.DeepEqual(valueA, valueB) {
+			return false
+		}
+	}
+	return true
+}
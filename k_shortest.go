@@ -0,0 +1,141 @@
+package dot
+
+import (
+	"fmt"
+	"math"
+)
+
+// KShortestPaths returns up to k loopless paths from vertex from to vertex to, ordered by increasing cost, using
+// Yen's algorithm over the configured costs. Costs must not be negative. Paths of equal cost are ordered by
+// discovery. The Explored field of the returned paths is not set.
+// If to cannot be reached, ErrNoPath is returned.
+func (g *Graph) KShortestPaths(from string, to string, k int) ([]*Path, error) {
+	return g.Compact().KShortestPaths(from, to, k)
+}
+
+// KShortestPaths returns up to k loopless paths from vertex from to vertex to using the precomputed costs of the
+// CompactGraph. See Graph.KShortestPaths.
+func (c *CompactGraph) KShortestPaths(from string, to string, k int) ([]*Path, error) {
+	source, exists := c.index[from]
+	if !exists {
+		return nil, fmt.Errorf("KShortestPaths() from %v: vertex not found", from)
+	}
+	target, exists := c.index[to]
+	if !exists {
+		return nil, fmt.Errorf("KShortestPaths() to %v: vertex not found", to)
+	}
+	if k < 1 {
+		return nil, fmt.Errorf("KShortestPaths() of %v paths: k must be positive", k)
+	}
+
+	distances, parents, _ := c.dijkstraFrom(source, nil, nil)
+	if math.IsInf(distances[target], 1) {
+		return nil, ErrNoPath
+	}
+	found := [][]int{c.pathTo(target, parents)}
+	var candidates [][]int
+	var candidateCosts []float64
+	for len(found) < k {
+		last := found[len(found)-1]
+		for i := 0; i < len(last)-1; i++ {
+			spur, root := last[i], last[:i+1]
+			// vertices of the root path can't be visited again, and the edges leaving spur along any known path
+			// sharing the same root are removed, so that every candidate is new
+			removed := make(map[int]bool, len(root))
+			for _, v := range root[:i] {
+				removed[v] = true
+			}
+			skipped := make(map[int]bool)
+			for _, path := range found {
+				if len(path) > i+1 && equalPaths(path[:i+1], root) {
+					skipped[path[i+1]] = true
+				}
+			}
+			blocked := func(edge int) bool {
+				next := c.targets[edge]
+				if removed[next] {
+					return true
+				}
+				return edge >= c.offsets[spur] && edge < c.offsets[spur+1] && skipped[next]
+			}
+			distances, parents, _ := c.dijkstraFrom(spur, nil, blocked)
+			if math.IsInf(distances[target], 1) {
+				continue
+			}
+			candidate := append(append([]int{}, root[:i]...), c.pathTo(target, parents)...)
+			if !containsPath(found, candidate) && !containsPath(candidates, candidate) {
+				candidates = append(candidates, candidate)
+				candidateCosts = append(candidateCosts, c.pathCost(candidate))
+			}
+		}
+		if len(candidates) == 0 {
+			break
+		}
+		best := 0
+		for i := range candidates {
+			if candidateCosts[i] < candidateCosts[best] {
+				best = i
+			}
+		}
+		found = append(found, candidates[best])
+		candidates = append(candidates[:best], candidates[best+1:]...)
+		candidateCosts = append(candidateCosts[:best], candidateCosts[best+1:]...)
+	}
+
+	paths := make([]*Path, len(found))
+	for i, ids := range found {
+		paths[i] = &Path{Vertices: make([]string, len(ids)), Cost: c.pathCost(ids)}
+		for j, id := range ids {
+			paths[i].Vertices[j] = c.names[id]
+		}
+	}
+	return paths, nil
+}
+
+// pathTo follows parents back from vertex and returns the IDs of the path in order.
+func (c *CompactGraph) pathTo(vertex int, parents []int) []int {
+	var path []int
+	for current := vertex; current != -1; current = parents[current] {
+		path = append(path, current)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// pathCost sums the cost of the cheapest edge between each pair of consecutive vertices of path.
+func (c *CompactGraph) pathCost(path []int) float64 {
+	cost := 0.0
+	for i := 0; i+1 < len(path); i++ {
+		cheapest := math.Inf(1)
+		for edge := c.offsets[path[i]]; edge < c.offsets[path[i]+1]; edge++ {
+			if c.targets[edge] == path[i+1] && c.weights[edge] < cheapest {
+				cheapest = c.weights[edge]
+			}
+		}
+		cost += cheapest
+	}
+	return cost
+}
+
+func equalPaths(a []int, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func containsPath(paths [][]int, path []int) bool {
+	for _, p := range paths {
+		if equalPaths(p, path) {
+			return true
+		}
+	}
+	return false
+}
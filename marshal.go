@@ -0,0 +1,310 @@
+package dot
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Struct fields are mapped to attributes through `dot` struct tags, in the form `dot:"name,options"`:
+//   - name is the attribute name. If empty, the field name is used. A name of "-" skips the field.
+//   - the omitempty option skips the field when marshalling a zero value.
+// Fields of struct type (or pointers to structs) are flattened, prefixing the names of their attributes with
+// the name of the field and an underscore, e.g. `dot:"pos"` on a struct with field `dot:"x"` maps to "pos_x".
+// Embedded structs without a tag are flattened without prefix. Unexported fields are ignored.
+
+// MarshalAttributes converts the tagged fields of v, a struct or a pointer to a struct, into an attribute map.
+// Integers, floats, bools and strings are stored as int, float64, bool and string respectively, matching the
+// types produced by the parser.
+func MarshalAttributes(v interface{}) (map[string]interface{}, error) {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil, fmt.Errorf("MarshalAttributes() of %T: nil pointer", v)
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("MarshalAttributes() of %T: value is not a struct", v)
+	}
+	attributes := make(map[string]interface{})
+	if err := marshalStruct(value, "", attributes); err != nil {
+		return nil, err
+	}
+	return attributes, nil
+}
+
+// UnmarshalAttributes stores the values of an attribute map in the tagged fields of v, which must be a pointer to
+// a struct. Values are converted to the type of the field where possible (e.g. the string "2.0" or the int 2 can be
+// stored in a float64 field). Fields whose attribute is missing are left untouched.
+func UnmarshalAttributes(attributes map[string]interface{}, v interface{}) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("UnmarshalAttributes() of %T: value is not a pointer to a struct", v)
+	}
+	return unmarshalStruct(attributes, value.Elem(), "")
+}
+
+// MarshalVertex sets the tagged fields of v as attributes of vertex, keeping any other attribute it already had.
+func (g *Graph) MarshalVertex(vertex string, v interface{}) error {
+	attributes, err := MarshalAttributes(v)
+	if err != nil {
+		return fmt.Errorf("MarshalVertex() of vertex %v: %v", vertex, err)
+	}
+	for attribute, value := range attributes {
+		g.SetVertexAttribute(vertex, attribute, value)
+	}
+	return nil
+}
+
+// UnmarshalVertex stores the attributes of vertex in the tagged fields of v.
+func (g *Graph) UnmarshalVertex(vertex string, v interface{}) error {
+	attributes, err := g.GetVertexAttributes(vertex)
+	if err != nil {
+		return err
+	}
+	if err = UnmarshalAttributes(attributes, v); err != nil {
+		return fmt.Errorf("UnmarshalVertex() of vertex %v: %v", vertex, err)
+	}
+	return nil
+}
+
+// MarshalEdge sets the tagged fields of v as attributes of the edge origin -> target, keeping any other attribute
+// it already had. If isDirectional is false, the attributes are set for target -> origin as well.
+func (g *Graph) MarshalEdge(origin string, target string, isDirectional bool, v interface{}) error {
+	attributes, err := MarshalAttributes(v)
+	if err != nil {
+		return fmt.Errorf("MarshalEdge() of edge %v -> %v: %v", origin, target, err)
+	}
+	for attribute, value := range attributes {
+		g.SetEdgeAttribute(origin, target, !isDirectional, attribute, value)
+	}
+	return nil
+}
+
+// UnmarshalEdge stores the attributes of the edge origin -> target in the tagged fields of v.
+func (g *Graph) UnmarshalEdge(origin string, target string, v interface{}) error {
+	attributes, err := g.GetEdgeAttributes(origin, target)
+	if err != nil {
+		return err
+	}
+	if err = UnmarshalAttributes(attributes, v); err != nil {
+		return fmt.Errorf("UnmarshalEdge() of edge %v -> %v: %v", origin, target, err)
+	}
+	return nil
+}
+
+// MarshalGraph sets the tagged fields of v as attributes of the graph itself.
+func (g *Graph) MarshalGraph(v interface{}) error {
+	attributes, err := MarshalAttributes(v)
+	if err != nil {
+		return fmt.Errorf("MarshalGraph() of graph %v: %v", g.Name, err)
+	}
+	for attribute, value := range attributes {
+		g.SetGraphAttribute(attribute, value)
+	}
+	return nil
+}
+
+// UnmarshalGraph stores the attributes of the graph itself in the tagged fields of v.
+func (g *Graph) UnmarshalGraph(v interface{}) error {
+	if err := UnmarshalAttributes(g.graphAttributes, v); err != nil {
+		return fmt.Errorf("UnmarshalGraph() of graph %v: %v", g.Name, err)
+	}
+	return nil
+}
+
+// StructMapper returns an AttributeMapper converting payloads of struct type T through their `dot` struct tags.
+func StructMapper[T any]() AttributeMapper[T] {
+	return AttributeMapper[T]{
+		FromAttributes: func(attributes map[string]interface{}) (T, error) {
+			var payload T
+			err := UnmarshalAttributes(attributes, &payload)
+			return payload, err
+		},
+		ToAttributes: func(payload T) (map[string]interface{}, error) {
+			return MarshalAttributes(payload)
+		},
+	}
+}
+
+// attributeField describes how a struct field maps to attributes.
+type attributeField struct {
+	name      string
+	omitEmpty bool
+	flatten   bool
+}
+
+// parseField reads the `dot` tag of field. ok is false if the field must be skipped.
+func parseField(field reflect.StructField, prefix string) (f attributeField, ok bool) {
+	tag, tagged := field.Tag.Lookup("dot")
+	if tag == "-" || (field.PkgPath != "" && !field.Anonymous) {
+		return f, false
+	}
+	options := strings.Split(tag, ",")
+	f.name = options[0]
+	for _, option := range options[1:] {
+		if option == "omitempty" {
+			f.omitEmpty = true
+		}
+	}
+
+	fieldType := field.Type
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+	f.flatten = fieldType.Kind() == reflect.Struct
+	if f.flatten && field.Anonymous && !tagged {
+		f.name = prefix
+		return f, true
+	}
+	if field.PkgPath != "" {
+		return f, false
+	}
+	if f.name == "" {
+		f.name = field.Name
+	}
+	if prefix != "" {
+		f.name = prefix + "_" + f.name
+	}
+	return f, true
+}
+
+func marshalStruct(value reflect.Value, prefix string, attributes map[string]interface{}) error {
+	for i := 0; i < value.NumField(); i++ {
+		f, ok := parseField(value.Type().Field(i), prefix)
+		if !ok {
+			continue
+		}
+		field := value.Field(i)
+		if f.omitEmpty && field.IsZero() {
+			continue
+		}
+		if field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				continue
+			}
+			field = field.Elem()
+		}
+		if f.flatten {
+			if err := marshalStruct(field, f.name, attributes); err != nil {
+				return err
+			}
+			continue
+		}
+		switch field.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			attributes[f.name] = int(field.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if field.Uint() > math.MaxInt {
+				return fmt.Errorf("attribute %v: value %v overflows int", f.name, field.Uint())
+			}
+			attributes[f.name] = int(field.Uint())
+		case reflect.Float32, reflect.Float64:
+			attributes[f.name] = field.Float()
+		case reflect.Bool:
+			attributes[f.name] = field.Bool()
+		case reflect.String:
+			attributes[f.name] = field.String()
+		default:
+			return fmt.Errorf("attribute %v: unsupported type %v", f.name, field.Type())
+		}
+	}
+	return nil
+}
+
+func unmarshalStruct(attributes map[string]interface{}, value reflect.Value, prefix string) error {
+	for i := 0; i < value.NumField(); i++ {
+		f, ok := parseField(value.Type().Field(i), prefix)
+		if !ok {
+			continue
+		}
+		field := value.Field(i)
+		if f.flatten {
+			if field.Kind() == reflect.Ptr {
+				if !hasPrefixedAttribute(attributes, f.name) {
+					continue
+				}
+				if field.IsNil() {
+					field.Set(reflect.New(field.Type().Elem()))
+				}
+				field = field.Elem()
+			}
+			if err := unmarshalStruct(attributes, field, f.name); err != nil {
+				return err
+			}
+			continue
+		}
+		attribute, exists := attributes[f.name]
+		if !exists {
+			continue
+		}
+		if field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				field.Set(reflect.New(field.Type().Elem()))
+			}
+			field = field.Elem()
+		}
+		if err := setField(field, attribute); err != nil {
+			return fmt.Errorf("attribute %v: %v", f.name, err)
+		}
+	}
+	return nil
+}
+
+// setField converts attribute to the type of field and stores it.
+func setField(field reflect.Value, attribute interface{}) error {
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		number, ok := toFloat64(attribute)
+		if !ok || number != float64(int64(number)) || field.OverflowInt(int64(number)) {
+			return fmt.Errorf("cannot store %v in a field of type %v", attribute, field.Type())
+		}
+		field.SetInt(int64(number))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		number, ok := toFloat64(attribute)
+		if !ok || number < 0 || number != float64(uint64(number)) || field.OverflowUint(uint64(number)) {
+			return fmt.Errorf("cannot store %v in a field of type %v", attribute, field.Type())
+		}
+		field.SetUint(uint64(number))
+	case reflect.Float32, reflect.Float64:
+		number, ok := toFloat64(attribute)
+		if !ok {
+			return fmt.Errorf("cannot store %v in a field of type %v", attribute, field.Type())
+		}
+		field.SetFloat(number)
+	case reflect.Bool:
+		switch boolean := attribute.(type) {
+		case bool:
+			field.SetBool(boolean)
+		case string:
+			parsed, err := strconv.ParseBool(boolean)
+			if err != nil {
+				return fmt.Errorf("cannot store %v in a field of type %v", attribute, field.Type())
+			}
+			field.SetBool(parsed)
+		default:
+			return fmt.Errorf("cannot store %v in a field of type %v", attribute, field.Type())
+		}
+	case reflect.String:
+		str, ok := attribute.(string)
+		if !ok {
+			str = fmt.Sprint(attribute)
+		}
+		field.SetString(str)
+	default:
+		return fmt.Errorf("unsupported type %v", field.Type())
+	}
+	return nil
+}
+
+func hasPrefixedAttribute(attributes map[string]interface{}, prefix string) bool {
+	for name := range attributes {
+		if prefix == "" || strings.HasPrefix(name, prefix+"_") {
+			return true
+		}
+	}
+	return false
+}
@@ -42,6 +42,12 @@ func Parse(fileStream []byte, verboseFlag bool) (bool, *Graph) {
 
 			_, fileStream = parseVertexAttributes(fileStream, g, sourceVertex)
 
+			// a vertex statement declares the vertex (and its attributes) without any edge
+			match, fileStream, _ = sliceMatch(fileStream, *endOfStatementRe)
+			if match {
+				continue
+			}
+
 			var edgeIsDirectional bool
 			match, fileStream, edgeIsDirectional = parseEdgeType(fileStream)
 			if !match {
@@ -152,6 +152,7 @@ func parseVertexAttributes(contents []byte, g *Graph, vertexName string) (match
 	match, contents, vertexAttributes := parseAttributes(contents)
 	if match {
 		g.vertexAttributes[vertexName] = vertexAttributes
+		g.invalidateVertexWeights("")
 	}
 	return match, contents
 }
@@ -176,6 +177,7 @@ func parseTargetVertexName(contents []byte, g *Graph, sourceVertex string, isDir
 		g.adjacencyMap[sourceVertex] = append(g.adjacencyMap[sourceVertex], g.fetchOrCreateVertex(targetVertex))
 		if !isDirectional {
 			g.adjacencyMap[targetVertex] = append(g.adjacencyMap[targetVertex], g.fetchOrCreateVertex(sourceVertex))
+			g.setUndirected(sourceVertex, targetVertex)
 		}
 		return true, contents, targetVertex
 	}
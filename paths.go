@@ -0,0 +1,375 @@
+package dot
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/christat/search"
+)
+
+// Algorithm selects the search strategy used by ShortestPath.
+type Algorithm int
+
+const (
+	// BreadthFirst finds the path with the fewest edges, regardless of costs.
+	BreadthFirst Algorithm = iota
+	// DepthFirst finds the first path reached by depth first traversal, which is not necessarily the shortest.
+	DepthFirst
+	// Dijkstra finds the cheapest path. Costs must not be negative; see BellmanFord otherwise.
+	Dijkstra
+	// AStar finds the cheapest path guided by vertex heuristics, which must be admissible for the result to be optimal.
+	// Heuristics which are not consistent (monotone) make it expand some vertices more than once.
+	AStar
+)
+
+func (a Algorithm) String() string {
+	switch a {
+	case BreadthFirst:
+		return "BreadthFirst"
+	case DepthFirst:
+		return "DepthFirst"
+	case Dijkstra:
+		return "Dijkstra"
+	case AStar:
+		return "AStar"
+	}
+	return fmt.Sprintf("Algorithm(%d)", int(a))
+}
+
+// ErrNoPath is returned by path queries when the target cannot be reached from the origin.
+var ErrNoPath = errors.New("no path found")
+
+// PathOptions configures a ShortestPath query.
+type PathOptions struct {
+	Algorithm Algorithm
+	// Profile, if set, names the profile used to evaluate costs and heuristics instead of the graph configuration.
+	Profile string
+}
+
+// Path is the result of a path query: the sequence of vertices from origin to target, the sum of the costs of its
+// edges, and the number of vertices expanded by the search.
+type Path struct {
+	Vertices []string
+	Cost     float64
+	Explored int
+}
+
+// pathState is implemented by Vertex, ProfiledVertex and CompactVertex.
+type pathState interface {
+	search.State
+	Cost(target search.State) float64
+	Heuristic() float64
+}
+
+// ShortestPath searches a path from vertex from to vertex to with the selected algorithm. Costs and heuristics are
+// evaluated through Vertex.Cost and Vertex.Heuristic, or through the profile named in opts.
+// If to cannot be reached, ErrNoPath is returned.
+func (g *Graph) ShortestPath(from string, to string, opts PathOptions) (*Path, error) {
+	var start pathState
+	if opts.Profile != "" {
+		vertex, err := g.ProfileVertex(opts.Profile, from)
+		if err != nil {
+			return nil, err
+		}
+		start = vertex
+	} else {
+		vertex, exists := g.vertexMap[from]
+		if !exists {
+			return nil, fmt.Errorf("ShortestPath() from %v: vertex not found", from)
+		}
+		start = vertex
+	}
+	if _, exists := g.vertexMap[to]; !exists {
+		return nil, fmt.Errorf("ShortestPath() to %v: vertex not found", to)
+	}
+	return findPath(start, to, opts.Algorithm)
+}
+
+// ShortestPath searches a path from vertex from to vertex to with the selected algorithm, using the costs and
+// heuristics precomputed in the CompactGraph. Profiles are not supported; see Graph.CompactProfile instead.
+func (c *CompactGraph) ShortestPath(from string, to string, opts PathOptions) (*Path, error) {
+	if opts.Profile != "" {
+		return nil, fmt.Errorf("ShortestPath() of compact graph %v: profiles are not supported", c.Name)
+	}
+	start := c.Vertex(from)
+	if start == nil {
+		return nil, fmt.Errorf("ShortestPath() from %v: vertex not found", from)
+	}
+	if _, exists := c.index[to]; !exists {
+		return nil, fmt.Errorf("ShortestPath() to %v: vertex not found", to)
+	}
+	return findPath(start, to, opts.Algorithm)
+}
+
+func findPath(start pathState, target string, algorithm Algorithm) (*Path, error) {
+	switch algorithm {
+	case BreadthFirst:
+		return breadthFirst(start, target)
+	case DepthFirst:
+		return depthFirst(start, target)
+	case Dijkstra:
+		return bestFirst(start, target, false)
+	case AStar:
+		return bestFirst(start, target, true)
+	}
+	return nil, fmt.Errorf("ShortestPath(): unknown algorithm %v", algorithm)
+}
+
+func breadthFirst(start pathState, target string) (*Path, error) {
+	parents := map[string]pathState{start.Name(): nil}
+	queue := []pathState{start}
+	explored := 0
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		if state.Name() == target {
+			return buildPath(state, parents, explored), nil
+		}
+		explored++
+		for _, neighbor := range state.Neighbors() {
+			if _, seen := parents[neighbor.Name()]; !seen {
+				parents[neighbor.Name()] = state
+				queue = append(queue, neighbor.(pathState))
+			}
+		}
+	}
+	return nil, ErrNoPath
+}
+
+func depthFirst(start pathState, target string) (*Path, error) {
+	parents := map[string]pathState{start.Name(): nil}
+	visited := make(map[string]bool)
+	stack := []pathState{start}
+	explored := 0
+	for len(stack) > 0 {
+		state := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if visited[state.Name()] {
+			continue
+		}
+		visited[state.Name()] = true
+		if state.Name() == target {
+			return buildPath(state, parents, explored), nil
+		}
+		explored++
+		// push neighbors in reverse, so that they are expanded in adjacency order
+		neighbors := state.Neighbors()
+		for i := len(neighbors) - 1; i >= 0; i-- {
+			if !visited[neighbors[i].Name()] {
+				parents[neighbors[i].Name()] = state
+				stack = append(stack, neighbors[i].(pathState))
+			}
+		}
+	}
+	return nil, ErrNoPath
+}
+
+// bestFirst implements both Dijkstra (useHeuristic == false) and A*. A closed vertex reached again at a lower cost
+// is reopened, which only happens with inconsistent heuristics.
+func bestFirst(start pathState, target string, useHeuristic bool) (*Path, error) {
+	parents := map[string]pathState{start.Name(): nil}
+	costs := map[string]float64{start.Name(): 0}
+	closed := make(map[string]bool)
+	open := &priorityQueue{}
+	heap.Push(open, &queueItem{state: start, priority: priority(start, 0, useHeuristic)})
+	explored := 0
+	for open.Len() > 0 {
+		item := heap.Pop(open).(*queueItem)
+		state := item.state
+		if closed[state.Name()] {
+			continue
+		}
+		if state.Name() == target {
+			return buildPath(state, parents, explored), nil
+		}
+		closed[state.Name()] = true
+		explored++
+		for _, neighbor := range state.Neighbors() {
+			cost := costs[state.Name()] + state.Cost(neighbor)
+			if previous, seen := costs[neighbor.Name()]; !seen || cost < previous {
+				delete(closed, neighbor.Name())
+				costs[neighbor.Name()] = cost
+				parents[neighbor.Name()] = state
+				next := neighbor.(pathState)
+				heap.Push(open, &queueItem{state: next, priority: priority(next, cost, useHeuristic)})
+			}
+		}
+	}
+	return nil, ErrNoPath
+}
+
+func priority(state pathState, cost float64, useHeuristic bool) float64 {
+	if useHeuristic {
+		return cost + state.Heuristic()
+	}
+	return cost
+}
+
+// buildPath follows parents back from state, summing the costs of the traversed edges.
+func buildPath(state pathState, parents map[string]pathState, explored int) *Path {
+	path := &Path{Explored: explored}
+	for current := state; current != nil; current = parents[current.Name()] {
+		path.Vertices = append(path.Vertices, current.Name())
+		if parent := parents[current.Name()]; parent != nil {
+			path.Cost += parent.Cost(current)
+		}
+	}
+	for i, j := 0, len(path.Vertices)-1; i < j; i, j = i+1, j-1 {
+		path.Vertices[i], path.Vertices[j] = path.Vertices[j], path.Vertices[i]
+	}
+	return path
+}
+
+// queueItem is an entry of priorityQueue. Ties are broken by insertion order to keep results deterministic.
+type queueItem struct {
+	state    pathState
+	priority float64
+	sequence int
+}
+
+type priorityQueue struct {
+	items    []*queueItem
+	sequence int
+}
+
+func (q *priorityQueue) Len() int { return len(q.items) }
+
+func (q *priorityQueue) Less(i, j int) bool {
+	if q.items[i].priority != q.items[j].priority {
+		return q.items[i].priority < q.items[j].priority
+	}
+	return q.items[i].sequence < q.items[j].sequence
+}
+
+func (q *priorityQueue) Swap(i, j int) { q.items[i], q.items[j] = q.items[j], q.items[i] }
+
+func (q *priorityQueue) Push(item interface{}) {
+	entry := item.(*queueItem)
+	entry.sequence = q.sequence
+	q.sequence++
+	q.items = append(q.items, entry)
+}
+
+func (q *priorityQueue) Pop() interface{} {
+	last := q.items[len(q.items)-1]
+	q.items = q.items[:len(q.items)-1]
+	return last
+}
+
+// dijkstraFrom computes the cheapest distances from source over the CSR arrays of c. If weights is not nil, it
+// replaces the costs of the graph (indexed like its edges); edges for which blocked returns true are ignored.
+// It returns the distances (+Inf if unreachable), the predecessor of each vertex (-1 if none) and the vertices
+// in the order they were settled.
+func (c *CompactGraph) dijkstraFrom(source int, weights []float64, blocked func(edge int) bool) (distances []float64,
+	parents []int, settled []int) {
+	if weights == nil {
+		weights = c.weights
+	}
+	distances = make([]float64, c.Order())
+	parents = make([]int, c.Order())
+	for v := range distances {
+		distances[v] = math.Inf(1)
+		parents[v] = -1
+	}
+	distances[source] = 0
+	done := make([]bool, c.Order())
+	open := &distanceHeap{{vertex: source}}
+	for open.Len() > 0 {
+		item := heap.Pop(open).(distanceItem)
+		if done[item.vertex] {
+			continue
+		}
+		done[item.vertex] = true
+		settled = append(settled, item.vertex)
+		for edge := c.offsets[item.vertex]; edge < c.offsets[item.vertex+1]; edge++ {
+			if blocked != nil && blocked(edge) {
+				continue
+			}
+			target := c.targets[edge]
+			if distance := item.distance + weights[edge]; distance < distances[target] {
+				distances[target] = distance
+				parents[target] = item.vertex
+				heap.Push(open, distanceItem{vertex: target, distance: distance})
+			}
+		}
+	}
+	return distances, parents, settled
+}
+
+// ShortestPathCounts runs Dijkstra's algorithm from the vertex identified by source, over the precomputed costs
+// of c, which must not be negative. It returns the distance of every vertex (+Inf if unreachable), the number of
+// cheapest paths reaching it, its predecessors on those paths, and the reached vertices in the order they were
+// settled, as needed by Brandes' betweenness centrality. Between two vertices, only the cheapest edge is considered,
+// and self loops are ignored.
+func (c *CompactGraph) ShortestPathCounts(source int) (distances []float64, counts []float64,
+	predecessors [][]int, settled []int) {
+	distances = make([]float64, c.Order())
+	counts = make([]float64, c.Order())
+	predecessors = make([][]int, c.Order())
+	for v := range distances {
+		distances[v] = math.Inf(1)
+	}
+	distances[source], counts[source] = 0, 1
+	done := make([]bool, c.Order())
+	open := &distanceHeap{{vertex: source}}
+	for open.Len() > 0 {
+		v := heap.Pop(open).(distanceItem).vertex
+		if done[v] {
+			continue
+		}
+		done[v] = true
+		settled = append(settled, v)
+		cheapest := make(map[int]float64)
+		for i, w := range c.Neighbors(v) {
+			if cost, exists := cheapest[w]; w != v && (!exists || c.Weights(v)[i] < cost) {
+				cheapest[w] = c.Weights(v)[i]
+			}
+		}
+		// neighbors are visited in ID order, so that results don't depend on map iteration
+		for _, w := range uniqueInts(c.Neighbors(v)) {
+			cost, exists := cheapest[w]
+			if !exists {
+				continue
+			}
+			distance := distances[v] + cost
+			switch {
+			case distance < distances[w]:
+				distances[w], counts[w], predecessors[w] = distance, counts[v], []int{v}
+				heap.Push(open, distanceItem{vertex: w, distance: distance})
+			case distance == distances[w] && !done[w]:
+				counts[w] += counts[v]
+				predecessors[w] = append(predecessors[w], v)
+			}
+		}
+	}
+	return distances, counts, predecessors, settled
+}
+
+type distanceItem struct {
+	vertex   int
+	distance float64
+}
+
+// distanceHeap orders vertices by distance, breaking ties by vertex ID to keep results deterministic.
+type distanceHeap []distanceItem
+
+func (h distanceHeap) Len() int { return len(h) }
+
+func (h distanceHeap) Less(i, j int) bool {
+	if h[i].distance != h[j].distance {
+		return h[i].distance < h[j].distance
+	}
+	return h[i].vertex < h[j].vertex
+}
+
+func (h distanceHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *distanceHeap) Push(item interface{}) { *h = append(*h, item.(distanceItem)) }
+
+func (h *distanceHeap) Pop() interface{} {
+	last := (*h)[len(*h)-1]
+	*h = (*h)[:len(*h)-1]
+	return last
+}
@@ -0,0 +1,205 @@
+package dot
+
+import "math/bits"
+
+// persistentMap is an immutable map from strings to values, stored as a hash array mapped trie. with and without
+// return a new map which shares every node off the path to the modified key with the receiver, so that they cost
+// O(log n) instead of a copy of the whole map. The zero value is an empty map.
+type persistentMap[V any] struct {
+	root *trieNode[V]
+	size int
+}
+
+// trieNode holds a slot for every bit set in bitmap, in increasing order. Below the depth at which hashes run out of
+// bits, nodes ignore bitmap and hold the colliding entries in any order. Nodes are never modified once created.
+type trieNode[V any] struct {
+	bitmap uint32
+	slots  []trieSlot[V]
+}
+
+// trieSlot is either a child node or an entry of the map.
+type trieSlot[V any] struct {
+	child *trieNode[V]
+	key   string
+	value V
+}
+
+const (
+	trieBits = 5
+	trieMask = 1<<trieBits - 1
+	hashBits = 64
+)
+
+// get returns the value stored for key, if any.
+func (m persistentMap[V]) get(key string) (V, bool) {
+	hash := hashKey(key)
+	node := m.root
+	for shift := 0; node != nil; shift += trieBits {
+		if shift >= hashBits {
+			for _, slot := range node.slots {
+				if slot.key == key {
+					return slot.value, true
+				}
+			}
+			break
+		}
+		bit := uint32(1) << (hash >> shift & trieMask)
+		if node.bitmap&bit == 0 {
+			break
+		}
+		slot := node.slots[bits.OnesCount32(node.bitmap&(bit-1))]
+		if slot.child == nil {
+			if slot.key == key {
+				return slot.value, true
+			}
+			break
+		}
+		node = slot.child
+	}
+	var zero V
+	return zero, false
+}
+
+// with returns a map in which key is set to value.
+func (m persistentMap[V]) with(key string, value V) persistentMap[V] {
+	root, added := m.root.with(hashKey(key), 0, key, value)
+	if added {
+		return persistentMap[V]{root, m.size + 1}
+	}
+	return persistentMap[V]{root, m.size}
+}
+
+// without returns a map which doesn't hold key. The receiver is returned if it doesn't hold key either.
+func (m persistentMap[V]) without(key string) persistentMap[V] {
+	root, removed := m.root.without(hashKey(key), 0, key)
+	if !removed {
+		return m
+	}
+	return persistentMap[V]{root, m.size - 1}
+}
+
+// len returns the number of keys in the map.
+func (m persistentMap[V]) len() int {
+	return m.size
+}
+
+// each calls visit for every key of the map, in no particular order.
+func (m persistentMap[V]) each(visit func(key string, value V)) {
+	m.root.each(visit)
+}
+
+func (n *trieNode[V]) with(hash uint64, shift int, key string, value V) (*trieNode[V], bool) {
+	if n == nil {
+		n = &trieNode[V]{}
+	}
+	if shift >= hashBits {
+		for i, slot := range n.slots {
+			if slot.key == key {
+				return n.replaced(i, trieSlot[V]{key: key, value: value}), false
+			}
+		}
+		return &trieNode[V]{slots: append(n.slots[:len(n.slots):len(n.slots)], trieSlot[V]{key: key, value: value})},
+			true
+	}
+	bit := uint32(1) << (hash >> shift & trieMask)
+	i := bits.OnesCount32(n.bitmap & (bit - 1))
+	if n.bitmap&bit == 0 {
+		slots := make([]trieSlot[V], 0, len(n.slots)+1)
+		slots = append(slots, n.slots[:i]...)
+		slots = append(slots, trieSlot[V]{key: key, value: value})
+		slots = append(slots, n.slots[i:]...)
+		return &trieNode[V]{n.bitmap | bit, slots}, true
+	}
+	slot := n.slots[i]
+	switch {
+	case slot.child != nil:
+		child, added := slot.child.with(hash, shift+trieBits, key, value)
+		return n.replaced(i, trieSlot[V]{child: child}), added
+	case slot.key == key:
+		return n.replaced(i, trieSlot[V]{key: key, value: value}), false
+	}
+	// the slot holds another key, which moves down to a new child shared with key
+	child, _ := (*trieNode[V])(nil).with(hashKey(slot.key), shift+trieBits, slot.key, slot.value)
+	child, _ = child.with(hash, shift+trieBits, key, value)
+	return n.replaced(i, trieSlot[V]{child: child}), true
+}
+
+// without returns the node without key, or nil if it ends up empty.
+func (n *trieNode[V]) without(hash uint64, shift int, key string) (*trieNode[V], bool) {
+	if n == nil {
+		return nil, false
+	}
+	if shift >= hashBits {
+		for i, slot := range n.slots {
+			if slot.key == key {
+				return n.removed(0, i), true
+			}
+		}
+		return n, false
+	}
+	bit := uint32(1) << (hash >> shift & trieMask)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+	i := bits.OnesCount32(n.bitmap & (bit - 1))
+	slot := n.slots[i]
+	if slot.child == nil {
+		if slot.key != key {
+			return n, false
+		}
+		return n.removed(bit, i), true
+	}
+	child, removed := slot.child.without(hash, shift+trieBits, key)
+	switch {
+	case !removed:
+		return n, false
+	case child == nil:
+		return n.removed(bit, i), true
+	case len(child.slots) == 1 && child.slots[0].child == nil:
+		// a child left with a single entry is folded back into its parent
+		return n.replaced(i, child.slots[0]), true
+	}
+	return n.replaced(i, trieSlot[V]{child: child}), true
+}
+
+// replaced returns a copy of the node in which slot i is replaced by slot.
+func (n *trieNode[V]) replaced(i int, slot trieSlot[V]) *trieNode[V] {
+	slots := make([]trieSlot[V], len(n.slots))
+	copy(slots, n.slots)
+	slots[i] = slot
+	return &trieNode[V]{n.bitmap, slots}
+}
+
+// removed returns a copy of the node without slot i, whose bit is bit, or nil if no slot remains.
+func (n *trieNode[V]) removed(bit uint32, i int) *trieNode[V] {
+	if len(n.slots) == 1 {
+		return nil
+	}
+	slots := make([]trieSlot[V], 0, len(n.slots)-1)
+	slots = append(slots, n.slots[:i]...)
+	slots = append(slots, n.slots[i+1:]...)
+	return &trieNode[V]{n.bitmap &^ bit, slots}
+}
+
+func (n *trieNode[V]) each(visit func(key string, value V)) {
+	if n == nil {
+		return
+	}
+	for _, slot := range n.slots {
+		if slot.child != nil {
+			slot.child.each(visit)
+		} else {
+			visit(slot.key, slot.value)
+		}
+	}
+}
+
+// hashKey returns the 64-bit FNV-1a hash of key.
+func hashKey(key string) uint64 {
+	hash := uint64(14695981039346656037)
+	for i := 0; i < len(key); i++ {
+		hash ^= uint64(key[i])
+		hash *= 1099511628211
+	}
+	return hash
+}
@@ -0,0 +1,115 @@
+package dot
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/christat/search"
+)
+
+// Profile combines a cost and a heuristic definition under a name, so that a single Graph can be searched with
+// several of them (e.g. "time" and "distance"). Fields follow the rules of their Graph counterparts: functions take
+// precedence over keys, and the graph defaults are used when neither is set.
+type Profile struct {
+	CostKey       string
+	HeuristicKey  string
+	CostFunc      func(origin, target *Vertex) float64
+	HeuristicFunc func(vertex *Vertex) float64
+}
+
+// SetProfile stores profile under the given name, replacing any previous profile with the same name.
+// Profiles must not be set while searches are running on the graph.
+func (g *Graph) SetProfile(name string, profile Profile) {
+	if g.profiles == nil {
+		g.profiles = make(map[string]Profile)
+	}
+	g.profiles[name] = profile
+}
+
+// Profile returns the profile stored under name, or an error if there is none.
+func (g *Graph) Profile(name string) (Profile, error) {
+	profile, exists := g.profiles[name]
+	if !exists {
+		return Profile{}, fmt.Errorf("Profile() of graph %v: profile %v not found", g.Name, name)
+	}
+	return profile, nil
+}
+
+// Profiles returns the names of all profiles of the graph, sorted alphabetically.
+func (g *Graph) Profiles() []string {
+	names := make([]string, 0, len(g.profiles))
+	for name := range g.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ProfileVertex returns a search.State view of vertex whose costs and heuristics are evaluated through the named
+// profile. Views bound to different profiles can be searched concurrently on the same graph.
+func (g *Graph) ProfileVertex(profile string, vertex string) (*ProfiledVertex, error) {
+	p, err := g.Profile(profile)
+	if err != nil {
+		return nil, err
+	}
+	v, exists := g.vertexMap[vertex]
+	if !exists {
+		return nil, fmt.Errorf("ProfileVertex() of vertex %v: vertex not found", vertex)
+	}
+	return &ProfiledVertex{Vertex: v, profile: &p}, nil
+}
+
+// CompactProfile builds the CompactGraph representation of g, evaluating costs and heuristics through the named
+// profile instead of the CostKey/HeuristicKey configuration of the graph.
+func (g *Graph) CompactProfile(profile string) (*CompactGraph, error) {
+	p, err := g.Profile(profile)
+	if err != nil {
+		return nil, err
+	}
+	return g.compact(
+		func(origin, target *Vertex) float64 { return g.cost(p.CostKey, p.CostFunc, origin, target) },
+		func(vertex *Vertex) float64 { return g.heuristic(p.HeuristicKey, p.HeuristicFunc, vertex) }), nil
+}
+
+// ProfiledVertex is a Vertex bound to a Profile. It implements search.State (and its weighted and heuristic
+// variants) like Vertex, but evaluates Cost and Heuristic through its profile. Its neighbors are bound to the same
+// profile.
+type ProfiledVertex struct {
+	*Vertex
+	profile *Profile
+}
+
+// Equals implements the search.State interface, comparing vertices by name like Vertex.Equals, regardless of
+// whether they are bound to a profile.
+func (v *ProfiledVertex) Equals(other search.State) bool {
+	return v.Vertex.Equals(other)
+}
+
+// Neighbors returns the vertices adjacent to the caller, bound to the same profile.
+func (v *ProfiledVertex) Neighbors() []search.State {
+	neighbors := v.Vertex.Neighbors()
+	profiled := make([]search.State, len(neighbors))
+	for i, neighbor := range neighbors {
+		profiled[i] = &ProfiledVertex{Vertex: neighbor.(*Vertex), profile: v.profile}
+	}
+	return profiled
+}
+
+// Cost evaluates the cost from v to target through the profile of v.
+func (v *ProfiledVertex) Cost(target search.State) float64 {
+	var t *Vertex
+	switch other := target.(type) {
+	case *ProfiledVertex:
+		t = other.Vertex
+	case *Vertex:
+		t = other
+	default:
+		return v.graph.DefaultCost()
+	}
+	return v.graph.cost(v.profile.CostKey, v.profile.CostFunc, v.Vertex, t)
+}
+
+// Heuristic evaluates the heuristic of v through its profile.
+func (v *ProfiledVertex) Heuristic() float64 {
+	return v.graph.heuristic(v.profile.HeuristicKey, v.profile.HeuristicFunc, v.Vertex)
+}
@@ -0,0 +1,265 @@
+package dot
+
+import (
+	"container/heap"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// SpanningAlgorithm selects the algorithm used by MinimumSpanningForest.
+type SpanningAlgorithm int
+
+const (
+	// Kruskal adds the cheapest remaining edges which don't close a cycle, in O(E log E) time.
+	Kruskal SpanningAlgorithm = iota
+	// Prim grows every tree from its alphabetically first vertex through the cheapest edge leaving it, in O(E log V) time.
+	Prim
+)
+
+// WeightedEdge is an edge along with its cost.
+type WeightedEdge struct {
+	Edge
+	Cost float64
+}
+
+// SpanningForest is the result of MinimumSpanningForest: the selected edges, with their endpoints in alphabetical
+// order, and the sum of their costs.
+type SpanningForest struct {
+	Edges []WeightedEdge
+	Cost  float64
+
+	graph *Graph
+}
+
+// MinimumSpanningForest computes a minimum spanning tree of every connected component of an undirected graph, using
+// the configured costs. Between two vertices, only the cheapest of the (possibly parallel) edges is considered.
+// Edges are listed in the order the algorithm selects them. Graphs whose Type is "digraph" are rejected.
+func (g *Graph) MinimumSpanningForest(algorithm SpanningAlgorithm) (*SpanningForest, error) {
+	if g.Type == "digraph" {
+		return nil, fmt.Errorf("MinimumSpanningForest() of graph %v: graph type is digraph, not graph", g.Name)
+	}
+	c := g.Compact()
+	edges := c.undirectedEdges()
+	var selected []int
+	switch algorithm {
+	case Kruskal:
+		selected = kruskal(c.Order(), edges)
+	case Prim:
+		selected = prim(c.Order(), edges)
+	default:
+		return nil, fmt.Errorf("MinimumSpanningForest(): unknown algorithm %v", algorithm)
+	}
+
+	forest := &SpanningForest{Edges: make([]WeightedEdge, len(selected)), graph: g}
+	for i, edge := range selected {
+		forest.Edges[i] = WeightedEdge{
+			Edge: Edge{Origin: c.names[edges[edge].origin], Target: c.names[edges[edge].target]},
+			Cost: edges[edge].cost,
+		}
+		forest.Cost += edges[edge].cost
+	}
+	return forest, nil
+}
+
+type costEdge struct {
+	origin int
+	target int
+	cost   float64
+}
+
+// undirectedEdges returns the cheapest edge between every pair of adjacent vertices, with origin < target, sorted
+// by cost and then by endpoints. Self loops are dropped.
+func (c *CompactGraph) undirectedEdges() []costEdge {
+	cheapest := make(map[[2]int]float64)
+	for v := 0; v < c.Order(); v++ {
+		weights := c.Weights(v)
+		for i, w := range c.Neighbors(v) {
+			if v == w {
+				continue
+			}
+			pair := [2]int{v, w}
+			if w < v {
+				pair = [2]int{w, v}
+			}
+			if cost, exists := cheapest[pair]; !exists || weights[i] < cost {
+				cheapest[pair] = weights[i]
+			}
+		}
+	}
+	edges := make([]costEdge, 0, len(cheapest))
+	for pair, cost := range cheapest {
+		edges = append(edges, costEdge{origin: pair[0], target: pair[1], cost: cost})
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].cost != edges[j].cost {
+			return edges[i].cost < edges[j].cost
+		}
+		if edges[i].origin != edges[j].origin {
+			return edges[i].origin < edges[j].origin
+		}
+		return edges[i].target < edges[j].target
+	})
+	return edges
+}
+
+// kruskal returns the indexes of the edges of a minimum spanning forest. edges must be sorted by cost.
+func kruskal(order int, edges []costEdge) []int {
+	sets := newDisjointSets(order)
+	var selected []int
+	for i, edge := range edges {
+		if sets.union(edge.origin, edge.target) {
+			selected = append(selected, i)
+		}
+	}
+	return selected
+}
+
+// prim returns the indexes of the edges of a minimum spanning forest. Ties are broken by position in edges.
+func prim(order int, edges []costEdge) []int {
+	incident := make([][]int, order)
+	for i, edge := range edges {
+		incident[edge.origin] = append(incident[edge.origin], i)
+		incident[edge.target] = append(incident[edge.target], i)
+	}
+	inTree := make([]bool, order)
+	var selected []int
+	for root := 0; root < order; root++ {
+		if inTree[root] {
+			continue
+		}
+		inTree[root] = true
+		open := &edgeHeap{edges: edges}
+		for _, edge := range incident[root] {
+			heap.Push(open, edge)
+		}
+		for open.Len() > 0 {
+			edge := heap.Pop(open).(int)
+			next := edges[edge].target
+			if inTree[next] {
+				next = edges[edge].origin
+			}
+			if inTree[next] {
+				continue
+			}
+			inTree[next] = true
+			selected = append(selected, edge)
+			for _, candidate := range incident[next] {
+				heap.Push(open, candidate)
+			}
+		}
+	}
+	return selected
+}
+
+// edgeHeap orders edge indexes by cost, breaking ties by index.
+type edgeHeap struct {
+	edges   []costEdge
+	indexes []int
+}
+
+func (h *edgeHeap) Len() int { return len(h.indexes) }
+
+func (h *edgeHeap) Less(i, j int) bool {
+	a, b := h.indexes[i], h.indexes[j]
+	if h.edges[a].cost != h.edges[b].cost {
+		return h.edges[a].cost < h.edges[b].cost
+	}
+	return a < b
+}
+
+func (h *edgeHeap) Swap(i, j int) { h.indexes[i], h.indexes[j] = h.indexes[j], h.indexes[i] }
+
+func (h *edgeHeap) Push(index interface{}) { h.indexes = append(h.indexes, index.(int)) }
+
+func (h *edgeHeap) Pop() interface{} {
+	last := h.indexes[len(h.indexes)-1]
+	h.indexes = h.indexes[:len(h.indexes)-1]
+	return last
+}
+
+// disjointSets is a union-find structure with path halving and union by size.
+type disjointSets struct {
+	parents []int
+	sizes   []int
+}
+
+func newDisjointSets(count int) *disjointSets {
+	sets := &disjointSets{parents: make([]int, count), sizes: make([]int, count)}
+	for i := range sets.parents {
+		sets.parents[i] = i
+		sets.sizes[i] = 1
+	}
+	return sets
+}
+
+func (s *disjointSets) find(element int) int {
+	for s.parents[element] != element {
+		s.parents[element] = s.parents[s.parents[element]]
+		element = s.parents[element]
+	}
+	return element
+}
+
+// union merges the sets of a and b, returning false if they were already the same set.
+func (s *disjointSets) union(a int, b int) bool {
+	a, b = s.find(a), s.find(b)
+	if a == b {
+		return false
+	}
+	if s.sizes[a] < s.sizes[b] {
+		a, b = b, a
+	}
+	s.parents[b] = a
+	s.sizes[a] += s.sizes[b]
+	return true
+}
+
+// Graph returns a new graph holding every vertex of the original graph and only the edges of the forest, along
+// with their attributes. Of the parallel edges between the endpoints of a forest edge, a single one is kept.
+func (f *SpanningForest) Graph() *Graph {
+	tree := f.edgeSet()
+	g := f.graph.subgraph(
+		func(string) bool { return true },
+		func(origin, target string) bool { return tree[Edge{Origin: origin, Target: target}] })
+	kept := make(map[Edge]int, len(tree))
+	for origin, neighbors := range g.adjacencyMap {
+		unique := neighbors[:0]
+		for _, neighbor := range neighbors {
+			edge := Edge{Origin: origin, Target: neighbor.Name()}
+			if kept[edge] < 1 {
+				kept[edge]++
+				unique = append(unique, neighbor)
+			}
+		}
+		g.adjacencyMap[origin] = unique
+	}
+	return g
+}
+
+// Highlighted returns a clone of the original graph in which the given attributes are set on both directions of
+// every edge of the forest, e.g. {"color": "red", "penwidth": 2} to highlight the forest when rendered.
+func (f *SpanningForest) Highlighted(attributes map[string]interface{}) *Graph {
+	g := f.graph.Clone()
+	for _, edge := range f.Edges {
+		for attribute, value := range attributes {
+			g.SetEdgeAttribute(edge.Origin, edge.Target, true, attribute, value)
+		}
+	}
+	return g
+}
+
+// WriteDOT writes the original graph to w in DOT syntax, with the edges of the forest drawn in bold red.
+func (f *SpanningForest) WriteDOT(w io.Writer) error {
+	return f.Highlighted(map[string]interface{}{"color": "red", "penwidth": 2}).WriteDOT(w)
+}
+
+// edgeSet returns the edges of the forest in both directions.
+func (f *SpanningForest) edgeSet() map[Edge]bool {
+	set := make(map[Edge]bool, 2*len(f.Edges))
+	for _, edge := range f.Edges {
+		set[edge.Edge] = true
+		set[Edge{Origin: edge.Target, Target: edge.Origin}] = true
+	}
+	return set
+}
@@ -0,0 +1,68 @@
+package dot_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/christat/dot"
+)
+
+func TestAllPairs(t *testing.T) {
+	g := parseWeighted(t, `digraph negative {
+		a -> [w=1] b;
+		b -> [w=-2] c;
+		a -> [w=4] c;
+		c -> [w=3] d;
+		d -> [w=1] a;
+	}`, "w")
+	for _, method := range []dot.AllPairsMethod{dot.FloydWarshall, dot.Johnson} {
+		result, err := g.Compact().AllPairs(method)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if distance, ok := result.Distance("b", "a"); !ok || distance != 2 {
+			t.Errorf("AllPairs() with method %v expected distance 2 from b to a, got %v", method, distance)
+		}
+		if hop, _ := result.NextHop("a", "d"); hop != "b" {
+			t.Errorf("AllPairs() with method %v expected next hop b from a to d, got %v", method, hop)
+		}
+		path, err := result.Path("d", "c")
+		if err != nil || !reflect.DeepEqual(path.Vertices, []string{"d", "a", "b", "c"}) || path.Cost != 0 {
+			t.Errorf("AllPairs() with method %v returned an incorrect path from d to c: %v", method, path)
+		}
+	}
+
+	g = parseWeighted(t, `digraph cyclic {
+		a -> [w=1] b;
+		b -> [w=-2] a;
+	}`, "w")
+	for _, method := range []dot.AllPairsMethod{dot.FloydWarshall, dot.Johnson} {
+		if _, err := g.Compact().AllPairs(method); err == nil {
+			t.Errorf("AllPairs() with method %v ignored a negative cycle", method)
+		}
+	}
+}
+
+func TestAllPairsCSV(t *testing.T) {
+	g := parseWeighted(t, `digraph chain {
+		a -> [w=1.5] b;
+		b -> [w=2] c;
+	}`, "w")
+	result, err := g.AllPairs()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	var buffer bytes.Buffer
+	if err = result.WriteCSV(&buffer); err != nil {
+		t.Error(err)
+		return
+	}
+	expected := strings.Join([]string{",a,b,c", "a,0,1.5,3.5", "b,,0,2", "c,,,0", ""}, "\n")
+	if buffer.String() != expected {
+		t.Errorf("WriteCSV() expected:\n%v\ngot:\n%v", expected, buffer.String())
+	}
+}
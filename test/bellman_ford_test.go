@@ -0,0 +1,69 @@
+package dot_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/christat/dot"
+)
+
+func parseWeighted(t *testing.T, source string, costKey string) *dot.Graph {
+	ok, g := dot.Parse([]byte(source), false)
+	if !ok {
+		t.Fatalf("Failed to parse graph %v", source)
+	}
+	g.CostKey = costKey
+	return g
+}
+
+func TestBellmanFord(t *testing.T) {
+	g := parseWeighted(t, `digraph negative {
+		a -> [w=1] b;
+		b -> [w=-2] c;
+		a -> [w=4] c;
+		c -> [w=3] d;
+		e -> [w=1] a;
+	}`, "w")
+	tree, err := g.BellmanFord("a")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if distance, ok := tree.Distance("d"); !ok || distance != 2 {
+		t.Errorf("BellmanFord() expected distance 2 to d, got %v", distance)
+	}
+	if _, ok := tree.Distance("e"); ok {
+		t.Error("BellmanFord() reached a vertex with no incoming path")
+	}
+	path, err := tree.PathTo("d")
+	if err != nil || !reflect.DeepEqual(path.Vertices, []string{"a", "b", "c", "d"}) {
+		t.Errorf("PathTo() returned an incorrect path: %v", path)
+	}
+	if _, err = tree.PathTo("e"); err != dot.ErrNoPath {
+		t.Errorf("PathTo() expected ErrNoPath, got %v", err)
+	}
+}
+
+func TestBellmanFordNegativeCycle(t *testing.T) {
+	g := parseWeighted(t, `digraph cyclic {
+		a -> [w=1] b;
+		b -> [w=1] c;
+		c -> [w=-3] b;
+		c -> [w=1] d;
+	}`, "w")
+	_, err := g.BellmanFord("a")
+	cycleErr, ok := err.(*dot.NegativeCycleError)
+	if !ok {
+		t.Errorf("BellmanFord() expected a NegativeCycleError, got %v", err)
+		return
+	}
+	cycle := cycleErr.Cycle
+	if len(cycle) != 3 || cycle[0] != cycle[2] {
+		t.Errorf("BellmanFord() reported an incorrect cycle %v", cycle)
+	}
+	for i := 0; i+1 < len(cycle); i++ {
+		if cycle[i] == cycle[i+1] || (cycle[i] != "b" && cycle[i] != "c") {
+			t.Errorf("BellmanFord() reported an incorrect cycle %v", cycle)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package dot_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/christat/dot"
+)
+
+func assignmentGraph(t *testing.T) *dot.Graph {
+	return parseWeighted(t, `graph tasks {
+		alice -- [cost=4] build;
+		alice -- [cost=2] deploy;
+		alice -- [cost=8] review;
+		bob -- [cost=4] build;
+		bob -- [cost=3] deploy;
+		bob -- [cost=6] review;
+		carol -- [cost=3] build;
+		carol -- [cost=1] deploy;
+		carol -- [cost=6] review;
+	}`, "cost")
+}
+
+func TestBipartition(t *testing.T) {
+	left, right, err := assignmentGraph(t).Bipartition()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !reflect.DeepEqual(left, []string{"alice", "bob", "carol"}) ||
+		!reflect.DeepEqual(right, []string{"build", "deploy", "review"}) {
+		t.Errorf("Bipartition() returned incorrect sides %v and %v", left, right)
+	}
+
+	filePath, _ := filepath.Abs("./dot_files/cyclic_undirected_graph.dot")
+	ok, g := dot.ParseFile(filePath)
+	if !ok {
+		t.Error("Failed to parse test file cyclic_undirected_graph.dot")
+		return
+	}
+	_, _, err = g.Bipartition()
+	cycleErr, ok := err.(*dot.OddCycleError)
+	if !ok {
+		t.Errorf("Bipartition() expected an OddCycleError, got %v", err)
+		return
+	}
+	cycle := cycleErr.Cycle
+	if len(cycle)%2 != 0 || cycle[0] != cycle[len(cycle)-1] {
+		t.Errorf("Bipartition() reported an incorrect odd cycle %v", cycle)
+	}
+	for i := 0; i+1 < len(cycle); i++ {
+		_, forward := g.GetEdgeAttributes(cycle[i], cycle[i+1])
+		_, backward := g.GetEdgeAttributes(cycle[i+1], cycle[i])
+		if forward != nil && backward != nil {
+			t.Errorf("Bipartition() reported a cycle through a missing edge: %v", cycle)
+		}
+	}
+}
+
+func TestMaximumMatching(t *testing.T) {
+	ok, g := dot.Parse([]byte(`graph jobs {
+		a -- x;
+		a -- y;
+		b -- x;
+		c -- x;
+		c -- z;
+		d -- z;
+	}`), false)
+	if !ok {
+		t.Error("Failed to parse jobs graph")
+		return
+	}
+	matching, err := g.MaximumMatching()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(matching) != 3 {
+		t.Errorf("MaximumMatching() expected 3 edges, got %v", matching)
+	}
+	used := make(map[string]bool)
+	for _, edge := range matching {
+		if used[edge.Origin] || used[edge.Target] {
+			t.Errorf("MaximumMatching() used a vertex twice: %v", matching)
+		}
+		used[edge.Origin], used[edge.Target] = true, true
+	}
+}
+
+func TestMinimumCostAssignment(t *testing.T) {
+	assignment, err := assignmentGraph(t).MinimumCostAssignment()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	expected := []dot.WeightedEdge{
+		{Edge: dot.Edge{Origin: "alice", Target: "deploy"}, Cost: 2},
+		{Edge: dot.Edge{Origin: "bob", Target: "review"}, Cost: 6},
+		{Edge: dot.Edge{Origin: "carol", Target: "build"}, Cost: 3},
+	}
+	if assignment.Cost != 11 || !reflect.DeepEqual(assignment.Edges, expected) {
+		t.Errorf("MinimumCostAssignment() expected %v with cost 11, got %v with cost %v", expected,
+			assignment.Edges, assignment.Cost)
+	}
+}
+
+func TestMinCostMaxFlow(t *testing.T) {
+	g := parseWeighted(t, `digraph shipping {
+		s -> [capacity=4, price=1] a;
+		s -> [capacity=2, price=5] b;
+		a -> [capacity=2, price=1] t;
+		a -> [capacity=3, price=1] b;
+		b -> [capacity=5, price=1] t;
+	}`, "price")
+	result, err := g.MinCostMaxFlow("s", "t", "capacity")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	// 2 units through s a t (cost 2 each), 2 through s a b t (3 each) and 2 through s b t (6 each)
+	if result.Value != 6 || result.Cost != 22 {
+		t.Errorf("MinCostMaxFlow() expected value 6 with cost 22, got %v with cost %v", result.Value, result.Cost)
+	}
+	if result.Flows[dot.Edge{Origin: "a", Target: "b"}] != 2 {
+		t.Errorf("MinCostMaxFlow() expected flow 2 on a -> b, got %v", result.Flows)
+	}
+
+	g = parseWeighted(t, `digraph arbitrage {
+		s -> [capacity=1, price=1] a;
+		a -> [capacity=1, price=1] b;
+		b -> [capacity=1, price=-3] a;
+		b -> [capacity=1, price=1] t;
+	}`, "price")
+	if _, err = g.MinCostMaxFlow("s", "t", "capacity"); err == nil {
+		t.Error("MinCostMaxFlow() ignored a negative cycle")
+	}
+}
@@ -0,0 +1,97 @@
+package dot_test
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/christat/dot"
+	"github.com/christat/dot/centrality"
+)
+
+func starGraph(t *testing.T) *dot.Graph {
+	ok, g := dot.Parse([]byte(`graph star {
+		hub -- [w=1] a;
+		hub -- [w=1] b;
+		hub -- [w=1] c;
+		a -- [w=5] b;
+	}`), false)
+	if !ok {
+		t.Fatal("Failed to parse star graph")
+	}
+	g.CostKey = "w"
+	return g
+}
+
+func TestCentrality(t *testing.T) {
+	g := starGraph(t)
+
+	degree := centrality.Degree(g)
+	if degree["hub"] != 1 || degree["c"] != 1.0/3 {
+		t.Errorf("Degree() returned incorrect scores %v", degree)
+	}
+	// edges declared with "--" in a digraph count once per endpoint, as in graphs
+	ok, mixed := dot.Parse([]byte(`digraph mixed { a -- b; b -> c; c -> b; }`), false)
+	if !ok {
+		t.Fatal("Failed to parse mixed graph")
+	}
+	if degree := centrality.Degree(mixed); degree["a"] != 0.5 || degree["b"] != 1.5 || degree["c"] != 1 {
+		t.Errorf("Degree() of mixed returned incorrect scores %v", degree)
+	}
+
+	closeness := centrality.Closeness(g)
+	if closeness["hub"] != 1 || closeness["c"] != 3.0/5 {
+		t.Errorf("Closeness() returned incorrect scores %v", closeness)
+	}
+
+	// the hub lies on the cheapest path between every pair of leaves, the a -- b edge being too expensive
+	betweenness := centrality.Betweenness(g)
+	if betweenness["hub"] != 1 || betweenness["a"] != 0 {
+		t.Errorf("Betweenness() returned incorrect scores %v", betweenness)
+	}
+	if ranking := betweenness.Ranking(); !reflect.DeepEqual(ranking, []string{"hub", "a", "b", "c"}) {
+		t.Errorf("Ranking() returned an incorrect order %v", ranking)
+	}
+
+	pageRank := centrality.PageRank(g, 0.85)
+	total := 0.0
+	for _, rank := range pageRank {
+		total += rank
+	}
+	if math.Abs(total-1) > 1e-9 || pageRank.Ranking()[0] != "hub" || pageRank["a"] <= pageRank["c"] {
+		t.Errorf("PageRank() returned incorrect scores %v", pageRank)
+	}
+}
+
+func TestCentralityDirected(t *testing.T) {
+	ok, g := dot.Parse([]byte(`digraph chain {
+		a -> b;
+		b -> c;
+	}`), false)
+	if !ok {
+		t.Error("Failed to parse chain graph")
+		return
+	}
+	if betweenness := centrality.Betweenness(g); betweenness["b"] != 0.5 {
+		t.Errorf("Betweenness() expected 0.5 for b, got %v", betweenness["b"])
+	}
+	// c is a dangling vertex, whose rank is redistributed
+	pageRank := centrality.PageRank(g, 0.85)
+	if pageRank["c"] <= pageRank["b"] || pageRank["b"] <= pageRank["a"] {
+		t.Errorf("PageRank() returned incorrect scores %v", pageRank)
+	}
+}
+
+func TestCentralityStore(t *testing.T) {
+	g := starGraph(t)
+	centrality.Store(g, centrality.Degree(g), "degree")
+	if degree, err := g.GetVertexAttribute("hub", "degree"); err != nil || degree != 1.0 {
+		t.Errorf("Store() expected degree 1 for hub, got %v", degree)
+	}
+	var buffer bytes.Buffer
+	if err := g.WriteDOT(&buffer); err != nil || !strings.Contains(buffer.String(), "\thub [degree=1.0];\n") {
+		t.Errorf("WriteDOT() didn't export stored scores:\n%v", buffer.String())
+	}
+}
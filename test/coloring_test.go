@@ -0,0 +1,116 @@
+package dot_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/christat/dot"
+)
+
+func TestColor(t *testing.T) {
+	// crown graph: a, c, e, g are each adjacent to b, d, f, h except their own partner
+	ok, g := dot.Parse([]byte(`graph crown {
+		a -- { d f h };
+		c -- { b f h };
+		e -- { b d h };
+		g -- { b d f };
+	}`), false)
+	if !ok {
+		t.Error("Failed to parse crown")
+		return
+	}
+	expected := map[dot.ColoringAlgorithm]int{dot.Greedy: 4, dot.DSatur: 2, dot.ExactColoring: 2}
+	for algorithm, count := range expected {
+		coloring, err := g.Color(dot.ColoringOptions{Algorithm: algorithm})
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if coloring.Count != count {
+			t.Errorf("Color(%v) used %v colors, expected %v", algorithm, coloring.Count, count)
+		}
+		for origin, neighbors := range g.AdjacencyMap() {
+			for _, neighbor := range neighbors {
+				if coloring.Colors[origin] == coloring.Colors[neighbor.Name()] {
+					t.Errorf("Color(%v) gave %v and %v the same color", algorithm, origin, neighbor.Name())
+				}
+			}
+		}
+	}
+
+	coloring, _ := g.Color(dot.ColoringOptions{Algorithm: dot.ExactColoring})
+	if classes := coloring.Classes(); !reflect.DeepEqual(classes, [][]string{{"a", "c", "e", "g"}, {"b", "d", "f", "h"}}) &&
+		!reflect.DeepEqual(classes, [][]string{{"b", "d", "f", "h"}, {"a", "c", "e", "g"}}) {
+		t.Errorf("Classes() = %v, expected the two sides of the crown", classes)
+	}
+	coloring.Store()
+	color := dot.ColorName(coloring.Colors["a"])
+	for _, attribute := range []string{"color", "fillcolor"} {
+		if value, err := g.GetVertexAttribute("a", attribute); err != nil || value != color {
+			t.Errorf("Store() set %v of a to %v (%v), expected %v", attribute, value, err, color)
+		}
+	}
+
+	// an odd cycle needs three colors, although its largest clique is an edge
+	ok, g = dot.Parse([]byte(`graph pentagon { a -- b; b -- c; c -- d; d -- e; e -- a; }`), false)
+	if !ok {
+		t.Error("Failed to parse pentagon")
+		return
+	}
+	if coloring, err := g.Color(dot.ColoringOptions{Algorithm: dot.ExactColoring}); err != nil || coloring.Count != 3 {
+		t.Errorf("Color() of pentagon = %v (%v), expected 3 colors", coloring, err)
+	}
+	if _, err := g.Color(dot.ColoringOptions{Algorithm: dot.ExactColoring, MaxVertices: 4}); !errors.Is(err, dot.ErrSearchLimit) {
+		t.Errorf("Color() error = %v, expected ErrSearchLimit", err)
+	}
+}
+
+func TestColorTimeout(t *testing.T) {
+	// Moon-Moser graph: 20 independent triples, adjacent to each other, with 3^20 maximal cliques
+	var source strings.Builder
+	source.WriteString("graph moon {\n")
+	for v := 0; v < 60; v++ {
+		for w := v + 1; w < 60; w++ {
+			if v/3 != w/3 {
+				fmt.Fprintf(&source, "\tv%v -- v%v;\n", v, w)
+			}
+		}
+	}
+	source.WriteString("}")
+	ok, g := dot.Parse([]byte(source.String()), false)
+	if !ok {
+		t.Error("Failed to parse moon")
+		return
+	}
+	start := time.Now()
+	coloring, err := g.Color(dot.ColoringOptions{Algorithm: dot.ExactColoring, Timeout: 10 * time.Millisecond})
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Color() with a timeout of 10ms took %v", elapsed)
+	}
+	if err != nil && !errors.Is(err, dot.ErrSearchLimit) {
+		t.Errorf("Color() error = %v, expected ErrSearchLimit", err)
+	}
+	if err == nil && coloring.Count != 20 {
+		t.Errorf("Color() used %v colors, expected 20", coloring.Count)
+	}
+}
+
+func TestMaximalCliques(t *testing.T) {
+	ok, g := dot.Parse([]byte(`digraph cliques { a -> b; b -> c; c -> a; b -> d; d -> c; d -> e; e -> d; f -> f; }`), false)
+	if !ok {
+		t.Error("Failed to parse cliques")
+		return
+	}
+	cliques := g.MaximalCliques()
+	expected := [][]string{{"a", "b", "c"}, {"b", "c", "d"}, {"d", "e"}, {"f"}}
+	if !reflect.DeepEqual(cliques, expected) {
+		t.Errorf("MaximalCliques() = %v, expected %v", cliques, expected)
+	}
+	if clique := g.MaximumClique(); !reflect.DeepEqual(clique, expected[0]) {
+		t.Errorf("MaximumClique() = %v, expected %v", clique, expected[0])
+	}
+}
@@ -0,0 +1,71 @@
+package dot_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/christat/dot"
+)
+
+func communityGraph(t *testing.T) *dot.Graph {
+	// two triangles joined by a weak link
+	ok, g := dot.Parse([]byte(`graph teams {
+		a -- [strength=3] b;
+		b -- [strength=3] c;
+		c -- [strength=3] a;
+		x -- [strength=3] y;
+		y -- [strength=3] z;
+		z -- [strength=3] x;
+		c -- [strength=1] x;
+	}`), false)
+	if !ok {
+		t.Fatal("Failed to parse teams graph")
+	}
+	return g
+}
+
+func TestCommunities(t *testing.T) {
+	g := communityGraph(t)
+	expected := [][]string{{"a", "b", "c"}, {"x", "y", "z"}}
+	for _, algorithm := range []dot.CommunityAlgorithm{dot.Louvain, dot.LabelPropagation} {
+		communities, err := g.Communities(dot.CommunityOptions{Algorithm: algorithm, WeightKey: "strength"})
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if !reflect.DeepEqual(communities, expected) {
+			t.Errorf("Communities() with algorithm %v expected %v, got %v", algorithm, expected, communities)
+		}
+	}
+
+	split, _ := g.Modularity([][]string{{"a", "b", "c"}, {"x", "y", "z"}}, "strength")
+	merged, _ := g.Modularity([][]string{{"a", "b", "c", "x", "y", "z"}}, "strength")
+	if split <= merged || merged != 0 {
+		t.Errorf("Modularity() expected the split partition to score higher, got %v and %v", split, merged)
+	}
+
+	g.SetEdgeAttribute("a", "b", true, "strength", "strong")
+	if _, err := g.Communities(dot.CommunityOptions{WeightKey: "strength"}); err == nil {
+		t.Error("Communities() accepted a non-numeric weight")
+	}
+}
+
+func TestWriteClusteredDOT(t *testing.T) {
+	g := communityGraph(t)
+	communities, _ := g.Communities(dot.CommunityOptions{})
+	var buffer bytes.Buffer
+	if err := g.WriteClusteredDOT(&buffer, communities); err != nil {
+		t.Error(err)
+		return
+	}
+	output := buffer.String()
+	if !strings.Contains(output, "\tsubgraph cluster_0 {\n\t\ta;\n\t\tb;\n\t\tc;\n\t}\n") ||
+		!strings.Contains(output, "\tsubgraph cluster_1 {\n\t\tx;\n\t\ty;\n\t\tz;\n\t}\n") {
+		t.Errorf("WriteClusteredDOT() didn't write the clusters:\n%v", output)
+	}
+	if !strings.Contains(output, "\tc -- x [strength=1];\n") {
+		t.Errorf("WriteClusteredDOT() didn't write the edges between clusters:\n%v", output)
+	}
+}
@@ -0,0 +1,116 @@
+package dot_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/christat/dot"
+)
+
+// gridGraph parses a size x size grid digraph whose vertices are connected to their right and lower neighbors,
+// with cost attribute k. Every vertex has a heuristic attribute h, its Manhattan distance to the last vertex.
+func gridGraph(tb testing.TB, size int) *dot.Graph {
+	var buffer bytes.Buffer
+	buffer.WriteString("digraph grid {\n")
+	for row := 0; row < size; row++ {
+		for column := 0; column < size; column++ {
+			h := 2*(size-1) - row - column
+			if column+1 < size {
+				fmt.Fprintf(&buffer, "r%vc%v [h=%v] -> [k=%v] r%vc%v;\n", row, column, h, 1+(row+column)%3, row, column+1)
+			}
+			if row+1 < size {
+				fmt.Fprintf(&buffer, "r%vc%v [h=%v] -> [k=%v] r%vc%v;\n", row, column, h, 1+(row*column)%3, row+1, column)
+			}
+		}
+	}
+	buffer.WriteString("}\n")
+	ok, g := dot.Parse(buffer.Bytes(), false)
+	if !ok {
+		tb.Fatal("Failed to parse generated grid graph")
+	}
+	g.CostKey = "k"
+	g.HeuristicKey = "h"
+	return g
+}
+
+func TestCompact(t *testing.T) {
+	g := generateGraph()
+	g.CostKey = "k"
+	c := g.Compact()
+	if c.Order() != 4 || c.Size() != 6 {
+		t.Errorf("Compact() expected 4 vertices and 6 edges, got %v and %v", c.Order(), c.Size())
+	}
+	id, exists := c.ID("C")
+	if !exists || c.VertexName(id) != "C" {
+		t.Error("Compact() failed to index vertex C")
+		return
+	}
+	target, _ := c.ID("t")
+	found := false
+	for i, neighbor := range c.Neighbors(id) {
+		if neighbor == target {
+			found = true
+			if c.Weights(id)[i] != 2 {
+				t.Errorf("Compact() stored cost %v for C -> t, expected 2", c.Weights(id)[i])
+			}
+		}
+	}
+	if !found {
+		t.Error("Compact() failed to store edge C -> t")
+	}
+
+	vertex := c.Vertex("C")
+	if vertex.Cost(c.Vertex("t")) != 2 || len(vertex.Neighbors()) != 2 {
+		t.Error("CompactVertex failed to answer cost or neighbor queries")
+	}
+	if !vertex.Neighbors()[0].Equals(c.Vertex(vertex.Neighbors()[0].Name())) {
+		t.Error("CompactVertex neighbors are not equal to the vertices of the same name")
+	}
+}
+
+func BenchmarkGraphTraversal(b *testing.B) {
+	g := gridGraph(b, 100)
+	start := g.VertexMap()["r0c0"]
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		total := 0.0
+		visited := map[string]bool{start.Name(): true}
+		queue := []*dot.Vertex{start}
+		for len(queue) > 0 {
+			vertex := queue[0]
+			queue = queue[1:]
+			for _, neighbor := range vertex.Neighbors() {
+				total += vertex.Cost(neighbor)
+				if !visited[neighbor.Name()] {
+					visited[neighbor.Name()] = true
+					queue = append(queue, neighbor.(*dot.Vertex))
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkCompactTraversal(b *testing.B) {
+	c := gridGraph(b, 100).Compact()
+	start, _ := c.ID("r0c0")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		total := 0.0
+		visited := make([]bool, c.Order())
+		visited[start] = true
+		queue := []int{start}
+		for len(queue) > 0 {
+			vertex := queue[0]
+			queue = queue[1:]
+			weights := c.Weights(vertex)
+			for j, neighbor := range c.Neighbors(vertex) {
+				total += weights[j]
+				if !visited[neighbor] {
+					visited[neighbor] = true
+					queue = append(queue, neighbor)
+				}
+			}
+		}
+	}
+}
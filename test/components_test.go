@@ -0,0 +1,52 @@
+package dot_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/christat/dot"
+)
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	ok, g := dot.Parse([]byte(`digraph services {
+		gateway -> auth;
+		auth -> users;
+		users -> auth;
+		users -> billing;
+		billing -> ledger;
+		ledger -> invoices;
+		invoices -> billing;
+		auth -> billing;
+		gateway -> ledger;
+		ledger -> audit;
+	}`), false)
+	if !ok {
+		t.Error("Failed to parse services graph")
+		return
+	}
+	components := g.StronglyConnectedComponents()
+	expected := [][]string{{"gateway"}, {"auth", "users"}, {"billing", "invoices", "ledger"}, {"audit"}}
+	if !reflect.DeepEqual(components, expected) {
+		t.Errorf("StronglyConnectedComponents() expected %v, got %v", expected, components)
+	}
+
+	condensation := g.Condensation()
+	if condensation.Type != "digraph" || len(condensation.VertexMap()) != len(expected) {
+		t.Errorf("Condensation() expected a digraph with %v vertices", len(expected))
+	}
+	if order, err := condensation.TopologicalSort(); err != nil || len(order) != len(expected) {
+		t.Errorf("Condensation() is not acyclic: %v", err)
+	}
+	if members, _ := condensation.GetVertexAttribute("scc2", "members"); members != "billing,invoices,ledger" {
+		t.Errorf("Condensation() expected members billing,invoices,ledger, got %v", members)
+	}
+	if size, _ := condensation.GetVertexAttribute("scc1", "size"); size != 2 {
+		t.Errorf("Condensation() expected size 2, got %v", size)
+	}
+	if edges, _ := condensation.GetEdgeAttribute("scc1", "scc2", "edges"); edges != 2 {
+		t.Errorf("Condensation() expected 2 edges from scc1 to scc2, got %v", edges)
+	}
+	if _, err := condensation.GetEdgeAttributes("scc2", "scc1"); err == nil {
+		t.Error("Condensation() added an edge against the direction of the original edges")
+	}
+}
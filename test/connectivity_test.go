@@ -0,0 +1,72 @@
+package dot_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/christat/dot"
+)
+
+func TestConnectivity(t *testing.T) {
+	ok, g := dot.Parse([]byte(`graph network {
+		a -- b;
+		b -- c;
+		c -- a;
+		c -- d;
+		d -- e;
+		d -- e;
+		e -- f;
+		x -- y;
+	}`), false)
+	if !ok {
+		t.Error("Failed to parse network graph")
+		return
+	}
+	components := g.ConnectedComponents()
+	expected := [][]string{{"a", "b", "c", "d", "e", "f"}, {"x", "y"}}
+	if !reflect.DeepEqual(components, expected) {
+		t.Errorf("ConnectedComponents() expected %v, got %v", expected, components)
+	}
+	points := g.ArticulationPoints()
+	if !reflect.DeepEqual(points, []string{"c", "d", "e"}) {
+		t.Errorf("ArticulationPoints() expected [c d e], got %v", points)
+	}
+	// d -- e is declared twice, so the parallel edges are not bridges
+	bridges := g.Bridges()
+	expectedBridges := []dot.Edge{{Origin: "c", Target: "d"}, {Origin: "e", Target: "f"}, {Origin: "x", Target: "y"}}
+	if !reflect.DeepEqual(bridges, expectedBridges) {
+		t.Errorf("Bridges() expected %v, got %v", expectedBridges, bridges)
+	}
+}
+
+func TestConnectivityCyclic(t *testing.T) {
+	filePath, _ := filepath.Abs("./dot_files/cyclic_undirected_graph.dot")
+	ok, g := dot.ParseFile(filePath)
+	if !ok {
+		t.Error("Failed to parse test file cyclic_undirected_graph.dot")
+		return
+	}
+	if components := g.ConnectedComponents(); len(components) != 1 {
+		t.Errorf("ConnectedComponents() expected a single component, got %v", components)
+	}
+	if points := g.ArticulationPoints(); len(points) != 0 {
+		t.Errorf("ArticulationPoints() expected none, got %v", points)
+	}
+	if bridges := g.Bridges(); len(bridges) != 0 {
+		t.Errorf("Bridges() expected none, got %v", bridges)
+	}
+}
+
+func TestConnectivityDirected(t *testing.T) {
+	// a -> b and b -> a are two edges, while the entries stored for b -- d form a single one
+	ok, g := dot.Parse([]byte(`digraph mixed { a -> b; b -> a; b -> c; b -- d; }`), false)
+	if !ok {
+		t.Error("Failed to parse mixed")
+		return
+	}
+	expected := []dot.Edge{{Origin: "b", Target: "c"}, {Origin: "b", Target: "d"}}
+	if bridges := g.Bridges(); !reflect.DeepEqual(bridges, expected) {
+		t.Errorf("Bridges() expected %v, got %v", expected, bridges)
+	}
+}
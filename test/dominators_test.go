@@ -0,0 +1,89 @@
+package dot_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/christat/dot"
+)
+
+func TestDominators(t *testing.T) {
+	// flow graph from the paper of Lengauer and Tarjan
+	ok, g := dot.Parse([]byte(`digraph flow {
+		r [kind=entry] -> { a b c };
+		a -> d;
+		b -> { a d e };
+		c -> { f g };
+		d -> l;
+		e -> h;
+		f -> i;
+		g -> { i j };
+		h -> { e k };
+		i -> k;
+		j -> i;
+		k -> { i r };
+		l -> h;
+		x -> r;
+	}`), false)
+	if !ok {
+		t.Error("Failed to parse flow graph")
+		return
+	}
+	tree, err := g.Dominators("r")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	expected := map[string]string{
+		"a": "r", "b": "r", "c": "r", "d": "r", "e": "r", "f": "c", "g": "c",
+		"h": "r", "i": "r", "j": "g", "k": "r", "l": "d",
+	}
+	if !reflect.DeepEqual(tree.Idom, expected) {
+		t.Errorf("Dominators() expected %v, got %v", expected, tree.Idom)
+	}
+	if !tree.Dominates("c", "j") || tree.Dominates("b", "d") || tree.Contains("x") {
+		t.Error("Dominates() returned incorrect results")
+	}
+	if children := tree.Children("c"); !reflect.DeepEqual(children, []string{"f", "g"}) {
+		t.Errorf("Children() expected [f g], got %v", children)
+	}
+
+	var buffer bytes.Buffer
+	if err = tree.Graph().WriteDOT(&buffer); err != nil {
+		t.Error(err)
+		return
+	}
+	output := buffer.String()
+	if !strings.Contains(output, "\tr [kind=entry];\n") || !strings.Contains(output, "\tg -> j;\n") ||
+		strings.Contains(output, "x") || strings.Count(output, "->") != len(expected) {
+		t.Errorf("Graph() returned an incorrect tree:\n%v", output)
+	}
+}
+
+func TestPostDominators(t *testing.T) {
+	ok, g := dot.Parse([]byte(`digraph branches {
+		entry -> { then else };
+		then -> join;
+		else -> join;
+		join -> { exit loop };
+		loop -> join;
+	}`), false)
+	if !ok {
+		t.Error("Failed to parse branches graph")
+		return
+	}
+	tree, err := g.PostDominators("exit")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	expected := map[string]string{"entry": "join", "then": "join", "else": "join", "join": "exit", "loop": "join"}
+	if !reflect.DeepEqual(tree.Idom, expected) {
+		t.Errorf("PostDominators() expected %v, got %v", expected, tree.Idom)
+	}
+	if _, err = g.PostDominators("missing"); err == nil {
+		t.Error("PostDominators() accepted a non-existent vertex")
+	}
+}
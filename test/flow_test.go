@@ -0,0 +1,89 @@
+package dot_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/christat/dot"
+)
+
+func TestMaxFlow(t *testing.T) {
+	ok, g := dot.Parse([]byte(`digraph network {
+		s -> [capacity=10] a;
+		s -> [capacity=5] b;
+		a -> [capacity=15] b;
+		a -> [capacity=4] t;
+		b -> [capacity=10] t;
+	}`), false)
+	if !ok {
+		t.Error("Failed to parse network graph")
+		return
+	}
+	result, err := g.MaxFlow("s", "t", "capacity")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if result.Value != 14 {
+		t.Errorf("MaxFlow() expected value 14, got %v", result.Value)
+	}
+	if !reflect.DeepEqual(result.SourceSide, []string{"a", "b", "s"}) || !reflect.DeepEqual(result.SinkSide, []string{"t"}) {
+		t.Errorf("MaxFlow() returned an incorrect cut: %v | %v", result.SourceSide, result.SinkSide)
+	}
+	if len(result.CutEdges) != 2 {
+		t.Errorf("MaxFlow() expected 2 cut edges, got %v", result.CutEdges)
+	}
+	out, in := 0.0, 0.0
+	for edge, flow := range result.Flows {
+		if edge.Origin == "s" {
+			out += flow
+		}
+		if edge.Target == "t" {
+			in += flow
+		}
+	}
+	if out != 14 || in != 14 {
+		t.Errorf("MaxFlow() flows don't add up: %v", result.Flows)
+	}
+
+	result.Store("flow")
+	if flow, err := g.GetEdgeAttribute("a", "t", "flow"); err != nil || flow != 4.0 {
+		t.Errorf("Store() expected flow 4 on a -> t, got %v", flow)
+	}
+
+	g.SetEdgeAttribute("s", "a", false, "capacity", -1)
+	if _, err = g.MaxFlow("s", "t", "capacity"); err == nil {
+		t.Error("MaxFlow() accepted a negative capacity")
+	}
+	if _, err = g.MaxFlow("s", "x", "capacity"); err == nil {
+		t.Error("MaxFlow() accepted a non-existent sink")
+	}
+}
+
+func TestMaxFlowUndirected(t *testing.T) {
+	ok, g := dot.Parse([]byte(`graph pipes {
+		s -- [c=3] a;
+		s -- [c=2] b;
+		a -- [c=2] b;
+		a -- [c=1] t;
+		b -- [c=4] t;
+	}`), false)
+	if !ok {
+		t.Error("Failed to parse pipes graph")
+		return
+	}
+	result, err := g.MaxFlow("s", "t", "c")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if result.Value != 5 {
+		t.Errorf("MaxFlow() expected value 5, got %v", result.Value)
+	}
+	if result.Flows[dot.Edge{Origin: "a", Target: "b"}] != 2 {
+		t.Errorf("MaxFlow() expected flow 2 from a to b, got %v", result.Flows)
+	}
+	if _, reverse := result.Flows[dot.Edge{Origin: "b", Target: "a"}]; reverse {
+		t.Error("MaxFlow() sent flow both ways")
+	}
+}
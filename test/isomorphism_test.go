@@ -0,0 +1,103 @@
+package dot_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/christat/dot"
+)
+
+func TestIsomorphic(t *testing.T) {
+	ok, g := dot.Parse([]byte(`digraph first {
+		a [role=db] -> [proto=tcp] b;
+		b -> c;
+		c -> a;
+		c -> d;
+	}`), false)
+	if !ok {
+		t.Error("Failed to parse first graph")
+		return
+	}
+	ok, renamed := dot.Parse([]byte(`digraph second {
+		z -> x [role=db];
+		x -> [proto=tcp] y;
+		y -> z;
+		z -> w;
+	}`), false)
+	if !ok {
+		t.Error("Failed to parse second graph")
+		return
+	}
+	mapping, isomorphic := g.Isomorphic(renamed, dot.MatchOptions{})
+	if !isomorphic || mapping["a"] != "x" || mapping["c"] != "z" || mapping["d"] != "w" {
+		t.Errorf("Isomorphic() returned an incorrect mapping %v", mapping)
+	}
+	opts := dot.MatchOptions{VertexAttributes: []string{"role"}, EdgeAttributes: []string{"proto"}}
+	if _, isomorphic = g.Isomorphic(renamed, opts); !isomorphic {
+		t.Error("Isomorphic() rejected graphs with equal attributes")
+	}
+	renamed.SetEdgeAttribute("x", "y", false, "proto", "udp")
+	if _, isomorphic = g.Isomorphic(renamed, opts); isomorphic {
+		t.Error("Isomorphic() ignored an edge attribute mismatch")
+	}
+
+	filePath, _ := filepath.Abs("./dot_files/graph3.dot")
+	ok, graph3 := dot.ParseFile(filePath)
+	if !ok {
+		t.Error("Failed to parse test file graph3.dot")
+		return
+	}
+	if _, isomorphic = graph3.Isomorphic(graph3.Clone(), dot.MatchOptions{VertexAttributes: []string{"h_cff"}}); !isomorphic {
+		t.Error("Isomorphic() rejected a clone")
+	}
+	if _, isomorphic = graph3.Isomorphic(g, dot.MatchOptions{}); isomorphic {
+		t.Error("Isomorphic() accepted graphs of different sizes")
+	}
+}
+
+func TestSubgraphMatches(t *testing.T) {
+	ok, g := dot.Parse([]byte(`digraph services {
+		api [kind=http] -> { users orders };
+		orders -> { users payments };
+		payments [kind=http] -> ledger;
+		ledger -> payments;
+	}`), false)
+	if !ok {
+		t.Error("Failed to parse services graph")
+		return
+	}
+	ok, triangle := dot.Parse([]byte(`digraph triangle {
+		a -> b;
+		a -> c;
+		b -> c;
+	}`), false)
+	if !ok {
+		t.Error("Failed to parse triangle graph")
+		return
+	}
+	matches := g.SubgraphMatches(triangle, dot.MatchOptions{})
+	if len(matches) != 1 || matches[0]["a"] != "api" || matches[0]["b"] != "orders" || matches[0]["c"] != "users" {
+		t.Errorf("SubgraphMatches() expected a single triangle api orders users, got %v", matches)
+	}
+
+	ok, pair := dot.Parse([]byte(`digraph pair {
+		a [kind=http] -> b;
+	}`), false)
+	if !ok {
+		t.Error("Failed to parse pair graph")
+		return
+	}
+	if matches = g.SubgraphMatches(pair, dot.MatchOptions{}); len(matches) != 6 {
+		t.Errorf("SubgraphMatches() expected one match per edge, got %v", matches)
+	}
+	if matches = g.SubgraphMatches(pair, dot.MatchOptions{VertexAttributes: []string{"kind"}}); len(matches) != 3 {
+		t.Errorf("SubgraphMatches() expected 3 matches from http services, got %v", matches)
+	}
+	// payments and ledger are connected both ways, which the pattern doesn't allow when induced
+	if matches = g.SubgraphMatches(pair, dot.MatchOptions{Induced: true}); len(matches) != 4 {
+		t.Errorf("SubgraphMatches() expected 4 induced matches, got %v", matches)
+	}
+	if matches = g.SubgraphMatches(pair, dot.MatchOptions{Limit: 2}); len(matches) != 2 {
+		t.Errorf("SubgraphMatches() ignored the limit, got %v", matches)
+	}
+}
@@ -0,0 +1,70 @@
+package dot_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/christat/dot"
+)
+
+func TestKShortestPaths(t *testing.T) {
+	// classic example from Yen's algorithm literature
+	g := parseWeighted(t, `digraph routes {
+		C -> [w=3] D;
+		C -> [w=2] E;
+		D -> [w=4] F;
+		E -> [w=1] D;
+		E -> [w=2] F;
+		E -> [w=3] G;
+		F -> [w=2] G;
+		F -> [w=1] H;
+		G -> [w=2] H;
+	}`, "w")
+	paths, err := g.KShortestPaths("C", "H", 3)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	expected := [][]string{{"C", "E", "F", "H"}, {"C", "E", "G", "H"}, {"C", "D", "F", "H"}}
+	costs := []float64{5, 7, 8}
+	if len(paths) != len(expected) {
+		t.Errorf("KShortestPaths() expected %v paths, got %v", len(expected), len(paths))
+		return
+	}
+	for i, path := range paths {
+		if i < 2 && !reflect.DeepEqual(path.Vertices, expected[i]) {
+			t.Errorf("KShortestPaths() expected path %v to be %v, got %v", i, expected[i], path.Vertices)
+		}
+		if path.Cost != costs[i] {
+			t.Errorf("KShortestPaths() expected path %v to cost %v, got %v", i, costs[i], path.Cost)
+		}
+	}
+
+	paths, _ = g.KShortestPaths("C", "H", 100)
+	seen := make(map[string]bool)
+	for i, path := range paths {
+		key := ""
+		visited := make(map[string]bool)
+		for _, vertex := range path.Vertices {
+			if visited[vertex] {
+				t.Errorf("KShortestPaths() returned a path with a loop: %v", path.Vertices)
+			}
+			visited[vertex] = true
+			key += vertex + " "
+		}
+		if seen[key] {
+			t.Errorf("KShortestPaths() returned path %v twice", path.Vertices)
+		}
+		seen[key] = true
+		if i > 0 && path.Cost < paths[i-1].Cost {
+			t.Error("KShortestPaths() returned paths out of order")
+		}
+	}
+	if len(paths) != 7 {
+		t.Errorf("KShortestPaths() expected all 7 paths, got %v", len(paths))
+	}
+
+	if _, err = g.KShortestPaths("H", "C", 2); err != dot.ErrNoPath {
+		t.Errorf("KShortestPaths() expected ErrNoPath, got %v", err)
+	}
+}
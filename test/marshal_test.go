@@ -0,0 +1,133 @@
+package dot_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/christat/dot"
+)
+
+type position struct {
+	X float64 `dot:"x"`
+	Y float64 `dot:"y"`
+}
+
+type station struct {
+	Label    string    `dot:"label"`
+	Capacity uint      `dot:"capacity,omitempty"`
+	Open     bool      `dot:"open"`
+	Cost     float32   `dot:"h_cff"`
+	Position position  `dot:"pos"`
+	Backup   *position `dot:"backup"`
+	Notes    string    `dot:"-"`
+	internal int
+}
+
+func TestMarshalVertex(t *testing.T) {
+	g := dot.NewGraph()
+	err := g.MarshalVertex("s", station{Label: "Start", Open: true, Cost: 2.5, Position: position{X: 1, Y: 2}, Notes: "n"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	attributes, _ := g.GetVertexAttributes("s")
+	if attributes["label"] != "Start" || attributes["open"] != true || attributes["h_cff"] != 2.5 {
+		t.Errorf("MarshalVertex() set incorrect attributes: %v", attributes)
+	}
+	if attributes["pos_x"] != 1.0 || attributes["pos_y"] != 2.0 {
+		t.Errorf("MarshalVertex() failed to flatten nested struct: %v", attributes)
+	}
+	for _, skipped := range []string{"capacity", "Notes", "internal", "backup_x"} {
+		if _, exists := attributes[skipped]; exists {
+			t.Errorf("MarshalVertex() set attribute %v, which should have been skipped", skipped)
+		}
+	}
+
+	if err = g.MarshalVertex("s", 42); err == nil {
+		t.Error("MarshalVertex() accepted a value which is not a struct")
+	}
+}
+
+func TestUnmarshalVertex(t *testing.T) {
+	g := generateGraph()
+	g.SetVertexAttribute("s", "capacity", 3)
+	g.SetVertexAttribute("s", "backup_y", 4)
+	var s station
+	if err := g.UnmarshalVertex("s", &s); err != nil {
+		t.Error(err)
+		return
+	}
+	// h_cff of vertex s is the string "2.0", which must be converted
+	if s.Label != "" || s.Capacity != 3 || s.Cost != 2.0 {
+		t.Errorf("UnmarshalVertex() set incorrect values: %+v", s)
+	}
+	if s.Backup == nil || s.Backup.Y != 4 {
+		t.Error("UnmarshalVertex() failed to allocate and fill a nested pointer struct")
+	}
+
+	g.SetVertexAttribute("s", "capacity", -1)
+	if err := g.UnmarshalVertex("s", &s); err == nil {
+		t.Error("UnmarshalVertex() stored a negative value in an unsigned field")
+	}
+	if err := g.UnmarshalVertex("foo", &s); err == nil {
+		t.Error("UnmarshalVertex() of a vertex without attributes didn't fail")
+	}
+}
+
+func TestMarshalEdgeAndGraph(t *testing.T) {
+	type edge struct {
+		K    int    `dot:"k"`
+		Name string `dot:"name,omitempty"`
+	}
+	g := generateGraph()
+	if err := g.MarshalEdge("t", "A", false, edge{K: 5}); err != nil {
+		t.Error(err)
+		return
+	}
+	var e edge
+	if err := g.UnmarshalEdge("A", "t", &e); err != nil || e.K != 5 {
+		t.Error("MarshalEdge() failed to set undirected edge attributes")
+	}
+
+	type settings struct {
+		Rankdir string `dot:"rankdir"`
+	}
+	if err := g.MarshalGraph(settings{Rankdir: "LR"}); err != nil {
+		t.Error(err)
+		return
+	}
+	var s settings
+	if err := g.UnmarshalGraph(&s); err != nil || s.Rankdir != "LR" {
+		t.Error("MarshalGraph() failed to round trip graph attributes")
+	}
+}
+
+func TestStructMapper(t *testing.T) {
+	mapper := dot.StructMapper[station]()
+	attributes, err := mapper.ToAttributes(station{Label: "Central", Open: true})
+	if value, _ := dot.StringAttribute(attributes, "label"); err != nil || value != "Central" {
+		t.Errorf("ToAttributes() = %v (%v), expected label Central", attributes, err)
+	}
+	payload, err := mapper.FromAttributes(attributes)
+	if err != nil || payload.Label != "Central" || !payload.Open {
+		t.Errorf("FromAttributes() = %+v (%v), expected the marshalled station", payload, err)
+	}
+
+	// untagged exported fields are marshalled too
+	type tagged struct {
+		Name string
+		Tags []string
+	}
+	if _, err := dot.StructMapper[tagged]().ToAttributes(tagged{Name: "a"}); err == nil {
+		t.Error("ToAttributes() accepted an unsupported field")
+	}
+	if _, err := dot.StructMapper[int]().ToAttributes(1); err == nil {
+		t.Error("ToAttributes() accepted a non-struct payload")
+	}
+	type counter struct {
+		Count uint64 `dot:"count"`
+	}
+	if _, err := dot.StructMapper[counter]().ToAttributes(counter{Count: math.MaxUint64}); err == nil {
+		t.Error("ToAttributes() accepted a value overflowing int")
+	}
+}
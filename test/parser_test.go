@@ -53,4 +53,17 @@ func TestInspectParsedFile(t *testing.T) {
 	if value != 7 {
 		t.Errorf("Value contained in inverse direction of edge is invalid")
 	}
-}
\ No newline at end of file
+}
+func TestParseVertexStatement(t *testing.T) {
+	ok, graph := dot.Parse([]byte(`graph g { a -- b; c; d [label="D"]; }`), false)
+	if !ok {
+		t.Error("Failed to parse vertex statements")
+		return
+	}
+	if _, exists := graph.VertexMap()["c"]; !exists || len(graph.AdjacencyMap()["c"]) != 0 {
+		t.Error("Parse() didn't declare vertex c without edges")
+	}
+	if label, err := graph.GetVertexAttribute("d", "label"); err != nil || label != "D" {
+		t.Errorf("Parse() didn't set the attributes of vertex d, got %v", label)
+	}
+}
@@ -0,0 +1,98 @@
+package dot_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/christat/dot"
+)
+
+func TestShortestPath(t *testing.T) {
+	g := profiledGraph(t)
+	g.CostKey = "k"
+	g.HeuristicKey = "h_cff"
+
+	expected := map[dot.Algorithm][]string{
+		dot.BreadthFirst: {"s", "A", "t"},
+		dot.DepthFirst:   {"s", "A", "t"},
+		dot.Dijkstra:     {"s", "C", "t"},
+		dot.AStar:        {"s", "C", "t"},
+	}
+	for algorithm, vertices := range expected {
+		path, err := g.ShortestPath("s", "t", dot.PathOptions{Algorithm: algorithm})
+		if err != nil {
+			t.Errorf("ShortestPath() with %v failed: %v", algorithm, err)
+			continue
+		}
+		if !reflect.DeepEqual(path.Vertices, vertices) {
+			t.Errorf("ShortestPath() with %v expected %v, got %v", algorithm, vertices, path.Vertices)
+		}
+		if path.Explored == 0 {
+			t.Errorf("ShortestPath() with %v didn't report explored vertices", algorithm)
+		}
+	}
+
+	path, _ := g.ShortestPath("s", "t", dot.PathOptions{Algorithm: dot.Dijkstra})
+	if path.Cost != 3 {
+		t.Errorf("ShortestPath() expected cost 3, got %v", path.Cost)
+	}
+	path, _ = g.ShortestPath("s", "t", dot.PathOptions{Algorithm: dot.Dijkstra, Profile: "hops"})
+	if path.Cost != 2 {
+		t.Errorf("ShortestPath() with profile expected cost 2, got %v", path.Cost)
+	}
+	path, _ = g.Compact().ShortestPath("s", "t", dot.PathOptions{Algorithm: dot.AStar})
+	if path == nil || path.Cost != 3 {
+		t.Error("CompactGraph.ShortestPath() failed to find the cheapest path")
+	}
+
+	if _, err := g.ShortestPath("t", "s", dot.PathOptions{Algorithm: dot.Dijkstra}); err != dot.ErrNoPath {
+		t.Errorf("ShortestPath() expected ErrNoPath, got %v", err)
+	}
+	if _, err := g.ShortestPath("s", "foo", dot.PathOptions{}); err == nil {
+		t.Error("ShortestPath() accepted a non-existent target")
+	}
+}
+
+func TestShortestPathInconsistentHeuristic(t *testing.T) {
+	// h of a is admissible (a reaches t at cost 4) but not consistent (a reaches b, with h 0, at cost 1), so b is
+	// first expanded through the direct edge from s and must be reopened
+	ok, g := dot.Parse([]byte(`digraph inconsistent {
+		s [h=0] -> [k=1] a [h=3];
+		s -> [k=3] b [h=0];
+		a -> [k=1] b;
+		b -> [k=3] t [h=0];
+	}`), false)
+	if !ok {
+		t.Error("Failed to parse inconsistent graph")
+		return
+	}
+	g.CostKey = "k"
+	g.HeuristicKey = "h"
+	path, err := g.ShortestPath("s", "t", dot.PathOptions{Algorithm: dot.AStar})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !reflect.DeepEqual(path.Vertices, []string{"s", "a", "b", "t"}) || path.Cost != 5 {
+		t.Errorf("ShortestPath() expected s a b t with cost 5, got %v with cost %v", path.Vertices, path.Cost)
+	}
+}
+
+func TestShortestPathUndirected(t *testing.T) {
+	filePath, _ := filepath.Abs("./dot_files/cyclic_undirected_graph.dot")
+	ok, g := dot.ParseFile(filePath)
+	if !ok {
+		t.Error("Failed to parse test file cyclic_undirected_graph.dot")
+		return
+	}
+	g.CostKey = "w"
+	path, err := g.ShortestPath("a", "e", dot.PathOptions{Algorithm: dot.Dijkstra})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !reflect.DeepEqual(path.Vertices, []string{"a", "c", "d", "e"}) || path.Cost != 9 {
+		t.Errorf("ShortestPath() expected a c d e with cost 9, got %v with cost %v", path.Vertices, path.Cost)
+	}
+}
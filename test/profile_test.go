@@ -0,0 +1,83 @@
+package dot_test
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/christat/dot"
+)
+
+func profiledGraph(t *testing.T) *dot.Graph {
+	filePath, _ := filepath.Abs("./dot_files/graph4.dot")
+	ok, g := dot.ParseFile(filePath)
+	if !ok {
+		t.Fatal("Failed to parse test file graph4.dot")
+	}
+	g.SetProfile("cff", dot.Profile{CostKey: "k", HeuristicKey: "h_cff"})
+	g.SetProfile("pdb", dot.Profile{CostKey: "k", HeuristicKey: "h_pdb"})
+	g.SetProfile("hops", dot.Profile{CostFunc: func(origin, target *dot.Vertex) float64 { return 1 }})
+	return g
+}
+
+func TestProfiles(t *testing.T) {
+	g := profiledGraph(t)
+	if names := g.Profiles(); len(names) != 3 || names[0] != "cff" {
+		t.Errorf("Profiles() returned unexpected names %v", names)
+	}
+	if _, err := g.Profile("foo"); err == nil {
+		t.Error("Profile() found a non-existent profile")
+	}
+
+	cff, err := g.ProfileVertex("cff", "C")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	pdb, _ := g.ProfileVertex("pdb", "C")
+	hops, _ := g.ProfileVertex("hops", "C")
+	if cff.Heuristic() != 3.14159 || pdb.Heuristic() != 10.0 || hops.Heuristic() != 0 {
+		t.Error("ProfileVertex() evaluated heuristics through the wrong profile")
+	}
+	for _, neighbor := range cff.Neighbors() {
+		if neighbor.Name() == "t" {
+			if cff.Cost(neighbor) != 2 || hops.Cost(neighbor) != 1 {
+				t.Error("ProfileVertex() evaluated costs through the wrong profile")
+			}
+			if _, ok := neighbor.(*dot.ProfiledVertex); !ok {
+				t.Error("ProfiledVertex.Neighbors() returned vertices which are not bound to the profile")
+			}
+		}
+	}
+	if _, err = g.ProfileVertex("cff", "foo"); err == nil {
+		t.Error("ProfileVertex() bound a non-existent vertex")
+	}
+	vertex := g.VertexMap()["C"]
+	if !cff.Equals(vertex) || !vertex.Equals(cff) || !cff.Equals(pdb) || vertex.Equals(g.VertexMap()["t"]) {
+		t.Error("Equals() didn't compare vertices and profiled vertices by name")
+	}
+
+	c, err := g.CompactProfile("pdb")
+	if err != nil || c.Vertex("C").Heuristic() != 10.0 {
+		t.Error("CompactProfile() failed to evaluate heuristics through the profile")
+	}
+}
+
+func TestConcurrentProfiles(t *testing.T) {
+	g := profiledGraph(t)
+	var wait sync.WaitGroup
+	for _, profile := range []string{"cff", "pdb", "hops", "cff", "pdb", "hops"} {
+		wait.Add(1)
+		go func(profile string) {
+			defer wait.Done()
+			vertex, _ := g.ProfileVertex(profile, "s")
+			for i := 0; i < 100; i++ {
+				for _, neighbor := range vertex.Neighbors() {
+					vertex.Cost(neighbor)
+					neighbor.(*dot.ProfiledVertex).Heuristic()
+				}
+			}
+		}(profile)
+	}
+	wait.Wait()
+}
@@ -0,0 +1,116 @@
+package dot_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestSnapshotIsolation(t *testing.T) {
+	g := generateGraph()
+	snapshot := g.Snapshot()
+	g.SetVertexAttribute("s", "name", "Changed")
+	g.SetEdgeAttribute("s", "A", false, "k", 42)
+
+	value, err := snapshot.GetVertexAttribute("s", "name")
+	if err != nil || value != "Start" {
+		t.Error("Snapshot() reflected a vertex attribute set after the snapshot was taken")
+	}
+	value, err = snapshot.GetEdgeAttribute("s", "A", "k")
+	if err != nil || value != 1 {
+		t.Error("Snapshot() reflected an edge attribute set after the snapshot was taken")
+	}
+}
+
+func TestSnapshotModifications(t *testing.T) {
+	base := generateGraph().Snapshot()
+
+	removed := base.WithoutVertex("A")
+	if removed.HasVertex("A") || !base.HasVertex("A") {
+		t.Error("WithoutVertex() failed to remove the vertex only from the derived snapshot")
+	}
+	for _, neighbor := range removed.Neighbors("s") {
+		if neighbor == "A" {
+			t.Error("WithoutVertex() kept edges pointing to the removed vertex")
+		}
+	}
+	if len(base.Neighbors("s")) != 2 {
+		t.Error("WithoutVertex() modified the adjacency of the parent snapshot")
+	}
+
+	overlay := base.WithVertexAttribute("C", "h_ff", 7).WithEdgeAttribute("C", "t", true, "k", 9)
+	value, _ := overlay.GetVertexAttribute("C", "h_ff")
+	if value != 7 {
+		t.Error("WithVertexAttribute() failed to set the attribute")
+	}
+	value, _ = base.GetVertexAttribute("C", "h_ff")
+	if value != 1 {
+		t.Error("WithVertexAttribute() modified the attributes of the parent snapshot")
+	}
+	value, _ = overlay.GetEdgeAttribute("C", "t", "k")
+	if value != 9 {
+		t.Error("WithEdgeAttribute() failed to set the attribute")
+	}
+	value, _ = base.GetEdgeAttribute("C", "t", "k")
+	if value != 2 {
+		t.Error("WithEdgeAttribute() modified the attributes of the parent snapshot")
+	}
+
+	extended := base.WithEdge("t", "new", false)
+	if !extended.HasVertex("new") || len(extended.Neighbors("new")) != 1 || len(base.Neighbors("t")) != 0 {
+		t.Error("WithEdge() failed to add an undirected edge only to the derived snapshot")
+	}
+	if len(extended.WithoutEdge("t", "new", false).Neighbors("t")) != 0 {
+		t.Error("WithoutEdge() failed to remove an undirected edge")
+	}
+}
+
+func TestSnapshotGraph(t *testing.T) {
+	g := generateGraph().Snapshot().WithoutEdge("s", "C", true).Graph()
+	vertex, exists := g.VertexMap()["s"]
+	if !exists {
+		t.Error("Snapshot.Graph() failed to restore vertices")
+		return
+	}
+	neighbors := vertex.Neighbors()
+	if len(neighbors) != 1 || neighbors[0].Name() != "A" {
+		t.Error("Snapshot.Graph() failed to restore the adjacency of the snapshot")
+		return
+	}
+	if neighbors[0] != g.VertexMap()["A"] {
+		t.Error("Snapshot.Graph() produced neighbors which do not belong to the new graph")
+	}
+}
+
+func TestSnapshotLarge(t *testing.T) {
+	base := gridGraph(t, 40).Snapshot()
+	derived := base
+	for row := 0; row < 40; row++ {
+		derived = derived.WithoutVertex(fmt.Sprintf("r%vc0", row))
+	}
+	if len(derived.Vertices()) != 40*39 || len(base.Vertices()) != 40*40 {
+		t.Errorf("WithoutVertex() left %v vertices, expected %v", len(derived.Vertices()), 40*39)
+	}
+	if !reflect.DeepEqual(derived.Neighbors("r0c1"), []string{"r0c2", "r1c1"}) || derived.HasVertex("r5c0") {
+		t.Error("WithoutVertex() removed the wrong vertices or edges")
+	}
+	if !base.HasVertex("r5c0") || len(base.Neighbors("r5c0")) != 2 {
+		t.Error("WithoutVertex() modified the parent snapshot")
+	}
+	extended := derived.WithEdge("r5c0", "r5c1", false)
+	if !extended.IsUndirectedEdge("r5c1", "r5c0") || derived.IsUndirectedEdge("r5c1", "r5c0") {
+		t.Error("WithEdge() failed to add an undirected edge only to the derived snapshot")
+	}
+}
+
+// BenchmarkSnapshotWithEdge adds one edge to snapshots of growing graphs: its cost must not grow with their size.
+func BenchmarkSnapshotWithEdge(b *testing.B) {
+	for _, size := range []int{10, 100, 300} {
+		base := gridGraph(b, size).Snapshot()
+		b.Run(fmt.Sprintf("vertices=%v", size*size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				base.WithEdge("r0c0", "r1c1", false).WithEdgeAttribute("r0c0", "r1c1", false, "k", i)
+			}
+		})
+	}
+}
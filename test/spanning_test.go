@@ -0,0 +1,171 @@
+package dot_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/christat/dot"
+)
+
+func TestMinimumSpanningForest(t *testing.T) {
+	g := parseWeighted(t, `graph cables {
+		a -- [w=4] b;
+		a -- [w=1] c;
+		b -- [w=2] c;
+		b -- [w=5] d;
+		c -- [w=8] d;
+		c -- [w=9] d;
+		x -- [w=3] y;
+	}`, "w")
+	for _, algorithm := range []dot.SpanningAlgorithm{dot.Kruskal, dot.Prim} {
+		forest, err := g.MinimumSpanningForest(algorithm)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if forest.Cost != 11 || len(forest.Edges) != 4 {
+			t.Errorf("MinimumSpanningForest() with algorithm %v expected 4 edges costing 11, got %v", algorithm,
+				forest.Edges)
+		}
+		tree := forest.Graph()
+		if components := tree.ConnectedComponents(); len(components) != 2 {
+			t.Errorf("MinimumSpanningForest() with algorithm %v doesn't span both components: %v", algorithm,
+				components)
+		}
+		if len(tree.Bridges()) != 4 {
+			t.Errorf("MinimumSpanningForest() with algorithm %v isn't a forest", algorithm)
+		}
+		if _, err = tree.GetEdgeAttribute("a", "b", "w"); err == nil {
+			t.Errorf("MinimumSpanningForest() with algorithm %v kept a discarded edge", algorithm)
+		}
+	}
+
+	forest, _ := g.MinimumSpanningForest(dot.Kruskal)
+	var buffer bytes.Buffer
+	if err := forest.WriteDOT(&buffer); err != nil {
+		t.Error(err)
+		return
+	}
+	output := buffer.String()
+	if !strings.Contains(output, "\ta -- c [color=red, penwidth=2, w=1];\n") ||
+		!strings.Contains(output, "\ta -- b [w=4];\n") {
+		t.Errorf("WriteDOT() didn't highlight the forest:\n%v", output)
+	}
+
+	ok, digraph := dot.ParseFile(filepath.Join("dot_files", "graph3.dot"))
+	if ok {
+		if _, err := digraph.MinimumSpanningForest(dot.Prim); err == nil {
+			t.Error("MinimumSpanningForest() accepted a digraph")
+		}
+	}
+
+	ok, parallel := dot.Parse([]byte(`graph parallel { a -- b; a -- b; b -- c; }`), false)
+	if !ok {
+		t.Error("Failed to parse parallel")
+		return
+	}
+	forest, _ = parallel.MinimumSpanningForest(dot.Kruskal)
+	tree := forest.Graph()
+	size := 0
+	for _, neighbors := range tree.AdjacencyMap() {
+		size += len(neighbors)
+	}
+	if size != 2*len(forest.Edges) || len(tree.Bridges()) != len(forest.Edges) {
+		t.Errorf("Graph() of forest %v kept parallel edges: %v", forest.Edges, tree.AdjacencyMap())
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	ok, g := dot.Parse([]byte(`digraph pipeline {
+		build [label="Build step"] -> [weight=2] test;
+		test -- deploy;
+	}`), false)
+	if !ok {
+		t.Error("Failed to parse pipeline graph")
+		return
+	}
+	g.SetGraphAttribute("rankdir", "LR")
+	var buffer bytes.Buffer
+	if err := g.WriteDOT(&buffer); err != nil {
+		t.Error(err)
+		return
+	}
+	expected := `digraph pipeline {
+	rankdir=LR;
+	build [label="Build step"];
+	deploy;
+	test;
+	build -> test [weight=2];
+	deploy -> test [dir=none];
+}
+`
+	if buffer.String() != expected {
+		t.Errorf("WriteDOT() expected:\n%v\ngot:\n%v", expected, buffer.String())
+	}
+}
+
+func TestWriteParsableDOT(t *testing.T) {
+	ok, g := dot.Parse([]byte(`digraph pipeline {
+		build [label="Build step", retries=3] -> [weight=2.0] test [ok=true];
+		deploy -- [cost=1.5] test;
+		test -> [weight=1] test;
+		deploy -- deploy;
+		build -> { lint deploy };
+		alone [priority=2];
+	}`), false)
+	if !ok {
+		t.Error("Failed to parse pipeline graph")
+		return
+	}
+	var buffer bytes.Buffer
+	if err := g.WriteParsableDOT(&buffer); err != nil {
+		t.Error(err)
+		return
+	}
+	written := buffer.String()
+	ok, read := dot.Parse(buffer.Bytes(), false)
+	if !ok {
+		t.Errorf("Parse() failed to read back WriteParsableDOT() output:\n%v", written)
+		return
+	}
+	if read.Name != g.Name || read.Type != g.Type {
+		t.Errorf("WriteParsableDOT() wrote graph %v %v, expected %v %v", read.Type, read.Name, g.Type, g.Name)
+	}
+	if priority, err := read.GetVertexAttribute("alone", "priority"); err != nil || priority != 2 {
+		t.Errorf("WriteParsableDOT() didn't write the vertex without edges:\n%v", written)
+	}
+	for origin, neighbors := range g.AdjacencyMap() {
+		if !reflect.DeepEqual(neighborNames(read, origin), neighborNames(g, origin)) {
+			t.Errorf("WriteParsableDOT() wrote neighbors %v of %v, expected %v:\n%v", neighborNames(read, origin),
+				origin, neighborNames(g, origin), written)
+		}
+		expected, _ := g.GetVertexAttributes(origin)
+		if attributes, _ := read.GetVertexAttributes(origin); !reflect.DeepEqual(attributes, expected) {
+			t.Errorf("WriteParsableDOT() wrote attributes %v of %v, expected %v", attributes, origin, expected)
+		}
+		for _, neighbor := range neighbors {
+			target := neighbor.Name()
+			expected, _ := g.GetEdgeAttributes(origin, target)
+			if attributes, _ := read.GetEdgeAttributes(origin, target); !reflect.DeepEqual(attributes, expected) {
+				t.Errorf("WriteParsableDOT() wrote attributes %v of %v -> %v, expected %v", attributes, origin,
+					target, expected)
+			}
+			if read.IsUndirectedEdge(origin, target) != g.IsUndirectedEdge(origin, target) {
+				t.Errorf("WriteParsableDOT() changed the direction of %v -> %v", origin, target)
+			}
+		}
+	}
+
+	buffer.Reset()
+	if err := g.WriteDOT(&buffer); err != nil || !strings.Contains(buffer.String(), "[weight=2.0]") {
+		t.Errorf("WriteDOT() didn't keep the float type of weight (%v):\n%v", err, buffer.String())
+	}
+
+	g.SetVertexAttribute("lint", "label", `say "hi"`)
+	if err := g.WriteParsableDOT(&buffer); err == nil {
+		t.Error("WriteParsableDOT() accepted a value with quotes")
+	}
+}
@@ -0,0 +1,52 @@
+package dot_test
+
+import (
+	"testing"
+)
+
+func TestClone(t *testing.T) {
+	g := generateGraph()
+	clone := g.Clone()
+	clone.SetVertexAttribute("s", "name", "Clone")
+	clone.SetEdgeAttribute("C", "t", false, "k", 5)
+
+	value, _ := g.GetVertexAttribute("s", "name")
+	if value != "Start" {
+		t.Error("Clone() shares vertex attribute maps with the original graph")
+	}
+	value, _ = g.GetEdgeAttribute("C", "t", "k")
+	if value != 2 {
+		t.Error("Clone() shares edge attribute maps with the original graph")
+	}
+	for _, neighbor := range clone.VertexMap()["s"].Neighbors() {
+		if neighbor != clone.VertexMap()[neighbor.Name()] {
+			t.Errorf("Clone() left vertex %v pointing to the original graph", neighbor.Name())
+		}
+	}
+}
+
+func TestInducedSubgraph(t *testing.T) {
+	sub := generateGraph().InducedSubgraph([]string{"s", "A", "foo"})
+	if len(sub.VertexMap()) != 2 {
+		t.Errorf("InducedSubgraph() expected 2 vertices, got %v", len(sub.VertexMap()))
+	}
+	neighbors := sub.VertexMap()["A"].Neighbors()
+	if len(neighbors) != 1 || neighbors[0].Name() != "s" {
+		t.Error("InducedSubgraph() kept edges to vertices outside of the subgraph")
+	}
+	if _, err := sub.GetEdgeAttribute("A", "t", "k"); err == nil {
+		t.Error("InducedSubgraph() kept attributes of edges outside of the subgraph")
+	}
+}
+
+func TestEdgeSubgraph(t *testing.T) {
+	sub := generateGraph().EdgeSubgraph(func(origin, target string, attributes map[string]interface{}) bool {
+		return attributes["k"] == 1
+	})
+	if _, exists := sub.VertexMap()["t"]; exists {
+		t.Error("EdgeSubgraph() kept a vertex not connected by any selected edge")
+	}
+	if len(sub.VertexMap()["s"].Neighbors()) != 2 || len(sub.VertexMap()["C"].Neighbors()) != 1 {
+		t.Error("EdgeSubgraph() failed to keep exactly the selected edges")
+	}
+}
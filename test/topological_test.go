@@ -0,0 +1,85 @@
+package dot_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/christat/dot"
+)
+
+func TestTopologicalSort(t *testing.T) {
+	ok, g := dot.Parse([]byte(`digraph pipeline {
+		fetch -> build;
+		lint -> build;
+		build -> test;
+		build -> package;
+		test -> deploy;
+		package -> deploy;
+		build -- cache;
+	}`), false)
+	if !ok {
+		t.Error("Failed to parse pipeline graph")
+		return
+	}
+	if !g.IsUndirectedEdge("cache", "build") || g.IsUndirectedEdge("build", "test") {
+		t.Error("IsUndirectedEdge() didn't tell -- edges apart from -> edges")
+	}
+	order, err := g.TopologicalSort()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	expected := []string{"cache", "fetch", "lint", "build", "package", "test", "deploy"}
+	if !reflect.DeepEqual(order, expected) {
+		t.Errorf("TopologicalSort() expected %v, got %v", expected, order)
+	}
+
+	if order, err = g.Clone().TopologicalSort(); err != nil || !reflect.DeepEqual(order, expected) {
+		t.Errorf("TopologicalSort() of a clone expected %v, got %v (%v)", expected, order, err)
+	}
+	if order, err = g.Snapshot().Graph().TopologicalSort(); err != nil || !reflect.DeepEqual(order, expected) {
+		t.Errorf("TopologicalSort() of a snapshot expected %v, got %v (%v)", expected, order, err)
+	}
+	if _, err = g.Snapshot().WithEdge("deploy", "fetch", false).Graph().TopologicalSort(); err != nil {
+		t.Errorf("TopologicalSort() took an undirected edge for a dependency: %v", err)
+	}
+	if _, err = g.Snapshot().WithEdge("deploy", "fetch", true).Graph().TopologicalSort(); err == nil {
+		t.Error("TopologicalSort() missed a cycle")
+	}
+}
+
+func TestTopologicalSortCycle(t *testing.T) {
+	filePath, _ := filepath.Abs("./dot_files/graph3.dot")
+	ok, g := dot.ParseFile(filePath)
+	if !ok {
+		t.Error("Failed to parse test file graph3.dot")
+		return
+	}
+	_, err := g.TopologicalSort()
+	cycleErr, ok := err.(*dot.CycleError)
+	if !ok {
+		t.Errorf("TopologicalSort() expected a CycleError, got %v", err)
+		return
+	}
+	cycle := cycleErr.Cycle
+	if len(cycle) < 3 || cycle[0] != cycle[len(cycle)-1] {
+		t.Errorf("TopologicalSort() reported an incorrect cycle %v", cycle)
+		return
+	}
+	for i := 0; i+1 < len(cycle); i++ {
+		if g.IsUndirectedEdge(cycle[i], cycle[i+1]) {
+			t.Errorf("TopologicalSort() reported a cycle through an undirected edge: %v", cycle)
+		}
+		if _, err = g.GetEdgeAttributes(cycle[i], cycle[i+1]); err != nil {
+			t.Errorf("TopologicalSort() reported a cycle through a missing edge: %v", cycle)
+		}
+	}
+
+	filePath, _ = filepath.Abs("./dot_files/cyclic_undirected_graph.dot")
+	if ok, g = dot.ParseFile(filePath); ok {
+		if _, err = g.TopologicalSort(); err == nil {
+			t.Error("TopologicalSort() accepted an undirected graph")
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package dot_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/christat/dot"
+)
+
+func TestEulerianPath(t *testing.T) {
+	// the bridges of Königsberg, plus one bridge making two land masses even
+	ok, g := dot.Parse([]byte(`graph bridges {
+		a -- b; a -- b; a -- c; a -- c; a -- d; b -- d; c -- d; b -- c;
+	}`), false)
+	if !ok {
+		t.Error("Failed to parse bridges")
+		return
+	}
+	path, err := g.EulerianPath()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	expected := []string{"a", "b", "a", "c", "a", "d", "b", "c", "d"}
+	if !reflect.DeepEqual(path, expected) {
+		t.Errorf("EulerianPath() = %v, expected %v", path, expected)
+	}
+	if _, err := g.EulerianCircuit(); !errors.Is(err, dot.ErrNoEulerianPath) {
+		t.Errorf("EulerianCircuit() error = %v, expected ErrNoEulerianPath", err)
+	}
+
+	ok, g = dot.Parse([]byte(`digraph loop { a -> b; b -> c; c -> a; c -> c; c -> d; d -> c; }`), false)
+	if !ok {
+		t.Error("Failed to parse loop")
+		return
+	}
+	circuit, err := g.EulerianCircuit()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	expected = []string{"a", "b", "c", "c", "d", "c", "a"}
+	if !reflect.DeepEqual(circuit, expected) {
+		t.Errorf("EulerianCircuit() = %v, expected %v", circuit, expected)
+	}
+
+	// a "--" loop is stored as two entries, which make a single edge, while the "->" loop is another one
+	ok, g = dot.Parse([]byte(`graph loops { a -- b; b -- a; b -- b; b -> b; }`), false)
+	if !ok {
+		t.Error("Failed to parse loops")
+		return
+	}
+	circuit, err = g.EulerianCircuit()
+	if expected = []string{"a", "b", "b", "b", "a"}; err != nil || !reflect.DeepEqual(circuit, expected) {
+		t.Errorf("EulerianCircuit() of loops = %v (%v), expected %v", circuit, err, expected)
+	}
+
+	// balanced degrees, but two components
+	ok, g = dot.Parse([]byte(`digraph split { a -> b; b -> a; c -> d; d -> c; }`), false)
+	if !ok {
+		t.Error("Failed to parse split")
+		return
+	}
+	if _, err := g.EulerianPath(); !errors.Is(err, dot.ErrNoEulerianPath) {
+		t.Errorf("EulerianPath() of split error = %v, expected ErrNoEulerianPath", err)
+	}
+}
+
+func TestHamiltonianPath(t *testing.T) {
+	ok, g := dot.Parse([]byte(`graph square { a -- b; b -- c; c -- d; d -- a; a -- c; e -- a; }`), false)
+	if !ok {
+		t.Error("Failed to parse square")
+		return
+	}
+	path, err := g.HamiltonianPath(dot.HamiltonianOptions{})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	// e is a leaf, so every hamiltonian path ends there
+	if len(path) != 5 || path[0] != "e" && path[4] != "e" {
+		t.Errorf("HamiltonianPath() = %v, expected a path through 5 vertices with e at one end", path)
+	}
+	if _, err := g.HamiltonianPath(dot.HamiltonianOptions{Cycle: true}); !errors.Is(err, dot.ErrNoHamiltonianPath) {
+		t.Errorf("HamiltonianPath() cycle error = %v, expected ErrNoHamiltonianPath", err)
+	}
+	if _, err := g.HamiltonianPath(dot.HamiltonianOptions{MaxVertices: 4}); !errors.Is(err, dot.ErrSearchLimit) {
+		t.Errorf("HamiltonianPath() error = %v, expected ErrSearchLimit", err)
+	}
+
+	ok, g = dot.Parse([]byte(`digraph ring { a -> b; b -> c; c -> d; d -> a; b -> d; }`), false)
+	if !ok {
+		t.Error("Failed to parse ring")
+		return
+	}
+	cycle, err := g.HamiltonianPath(dot.HamiltonianOptions{Cycle: true, Timeout: time.Second})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if expected := []string{"a", "b", "c", "d", "a"}; !reflect.DeepEqual(cycle, expected) {
+		t.Errorf("HamiltonianPath() = %v, expected %v", cycle, expected)
+	}
+
+	// a cycle through two vertices can't go back through the edge it came from
+	cycles := map[string][]string{
+		`graph pair { a -- b; }`:           nil,
+		`digraph pair { a -- b; }`:         nil,
+		`graph pair { a -- b; b -- a; }`:   {"a", "b", "a"},
+		`digraph pair { a -> b; b -> a; }`: {"a", "b", "a"},
+	}
+	for source, expected := range cycles {
+		ok, g = dot.Parse([]byte(source), false)
+		if !ok {
+			t.Errorf("Failed to parse %v", source)
+			continue
+		}
+		cycle, err := g.HamiltonianPath(dot.HamiltonianOptions{Cycle: true})
+		if expected == nil && !errors.Is(err, dot.ErrNoHamiltonianPath) || !reflect.DeepEqual(cycle, expected) {
+			t.Errorf("HamiltonianPath() of %v = %v (%v), expected %v", source, cycle, err, expected)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package dot_test
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/christat/dot"
+)
+
+func dependencyGraph(t *testing.T) *dot.Graph {
+	ok, g := dot.Parse([]byte(`digraph dependencies {
+		app [team=web] -> [reason=direct] lib;
+		app -> [reason=redundant] core;
+		app -> [reason=redundant] log;
+		lib -> core;
+		core -> log;
+		lib -> log;
+		app -- docs;
+	}`), false)
+	if !ok {
+		t.Fatal("Failed to parse dependencies graph")
+	}
+	return g
+}
+
+func neighborNames(g *dot.Graph, vertex string) []string {
+	var names []string
+	for _, neighbor := range g.AdjacencyMap()[vertex] {
+		names = append(names, neighbor.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestTransitiveReduction(t *testing.T) {
+	g := dependencyGraph(t)
+	reduced, err := g.TransitiveReduction()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	expected := map[string][]string{"app": {"docs", "lib"}, "lib": {"core"}, "core": {"log"}, "log": nil, "docs": {"app"}}
+	for vertex, neighbors := range expected {
+		if names := neighborNames(reduced, vertex); !reflect.DeepEqual(names, neighbors) {
+			t.Errorf("TransitiveReduction() expected %v to lead to %v, got %v", vertex, neighbors, names)
+		}
+	}
+	if team, _ := reduced.GetVertexAttribute("app", "team"); team != "web" {
+		t.Error("TransitiveReduction() dropped vertex attributes")
+	}
+	if reason, _ := reduced.GetEdgeAttribute("app", "lib", "reason"); reason != "direct" {
+		t.Error("TransitiveReduction() dropped the attributes of a retained edge")
+	}
+	if _, err = reduced.GetEdgeAttributes("app", "core"); err == nil {
+		t.Error("TransitiveReduction() kept the attributes of a dropped edge")
+	}
+	if len(g.AdjacencyMap()["app"]) != 4 {
+		t.Error("TransitiveReduction() modified the original graph")
+	}
+
+	// the reduction is written by the -reduce flag of the executable, whose output has to be readable by -f
+	var buffer bytes.Buffer
+	if err = reduced.WriteParsableDOT(&buffer); err != nil {
+		t.Error(err)
+		return
+	}
+	ok, read := dot.Parse(buffer.Bytes(), false)
+	if !ok {
+		t.Errorf("Parse() failed to read back the reduction:\n%v", buffer.String())
+		return
+	}
+	for vertex, neighbors := range expected {
+		if names := neighborNames(read, vertex); !reflect.DeepEqual(names, neighbors) {
+			t.Errorf("Parse() of the written reduction expected %v to lead to %v, got %v", vertex, neighbors, names)
+		}
+	}
+	if reason, _ := read.GetEdgeAttribute("app", "lib", "reason"); reason != "direct" {
+		t.Error("Parse() of the written reduction lost the attributes of a retained edge")
+	}
+
+	ok, cyclic := dot.Parse([]byte(`digraph cyclic {
+		a -> b;
+		b -> a;
+	}`), false)
+	if ok {
+		if _, err = cyclic.TransitiveReduction(); err == nil {
+			t.Error("TransitiveReduction() accepted a cyclic graph")
+		}
+	}
+}
+
+func TestTransitiveClosure(t *testing.T) {
+	reduced, _ := dependencyGraph(t).TransitiveReduction()
+	closure, err := reduced.TransitiveClosure()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if names := neighborNames(closure, "app"); !reflect.DeepEqual(names, []string{"core", "docs", "lib", "log"}) {
+		t.Errorf("TransitiveClosure() expected app to lead to core, docs, lib and log, got %v", names)
+	}
+	if names := neighborNames(closure, "docs"); !reflect.DeepEqual(names, []string{"app"}) {
+		t.Errorf("TransitiveClosure() followed an undirected edge: %v", names)
+	}
+	if reason, _ := closure.GetEdgeAttribute("app", "lib", "reason"); reason != "direct" {
+		t.Error("TransitiveClosure() dropped the attributes of an existing edge")
+	}
+
+	ok, cyclic := dot.Parse([]byte(`digraph cyclic {
+		a -> b;
+		b -> a;
+	}`), false)
+	if ok {
+		closure, _ = cyclic.TransitiveClosure()
+		if names := neighborNames(closure, "a"); !reflect.DeepEqual(names, []string{"a", "b"}) {
+			t.Errorf("TransitiveClosure() expected a to reach itself on a cycle, got %v", names)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package dot_test
+
+import (
+	"math"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/christat/dot"
+)
+
+type typedVertex struct {
+	Name string
+	Cff  float64
+}
+
+type typedEdge struct {
+	Weight float64
+}
+
+var typedVertexMapper = dot.AttributeMapper[typedVertex]{
+	FromAttributes: func(attributes map[string]interface{}) (typedVertex, error) {
+		name, _ := dot.StringAttribute(attributes, "name")
+		cff, _ := dot.FloatAttribute(attributes, "h_cff")
+		return typedVertex{Name: name, Cff: cff}, nil
+	},
+	ToAttributes: func(vertex typedVertex) (map[string]interface{}, error) {
+		return map[string]interface{}{"name": vertex.Name, "h_cff": vertex.Cff}, nil
+	},
+}
+
+var typedEdgeMapper = dot.AttributeMapper[typedEdge]{
+	FromAttributes: func(attributes map[string]interface{}) (typedEdge, error) {
+		weight, err := dot.FloatAttribute(attributes, "k")
+		return typedEdge{Weight: weight}, err
+	},
+	ToAttributes: func(edge typedEdge) (map[string]interface{}, error) {
+		return map[string]interface{}{"k": edge.Weight}, nil
+	},
+}
+
+func TestFromGraph(t *testing.T) {
+	filePath, _ := filepath.Abs("./dot_files/graph2.dot")
+	ok, g := dot.ParseFile(filePath)
+	if !ok {
+		t.Error("Failed to parse test file graph2.dot")
+		return
+	}
+	typed, err := dot.FromGraph(g, typedVertexMapper, typedEdgeMapper)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	s, _ := typed.Vertex("s")
+	if s.Name != "Start" || s.Cff != 2.0 {
+		t.Errorf("FromGraph() built an incorrect vertex payload: %+v", s)
+	}
+	edge, exists := typed.Edge("t", "B")
+	if !exists || edge.Weight != 1 {
+		t.Errorf("FromGraph() built an incorrect edge payload: %+v", edge)
+	}
+
+	_, err = dot.FromGraph(generateGraph(), typedVertexMapper, dot.AttributeMapper[typedEdge]{
+		FromAttributes: func(attributes map[string]interface{}) (typedEdge, error) {
+			weight, err := dot.FloatAttribute(attributes, "h_cff")
+			return typedEdge{Weight: weight}, err
+		},
+	})
+	if err == nil {
+		t.Error("FromGraph() ignored an edge conversion error")
+	}
+}
+
+func TestTypedGraph(t *testing.T) {
+	typed := dot.NewTypedGraph[typedVertex, typedEdge]()
+	typed.AddVertex("a", typedVertex{Name: "A", Cff: 1.5})
+	typed.AddEdge("a", "b", false, typedEdge{Weight: 4})
+	typed.Cost = func(edge typedEdge) float64 { return edge.Weight }
+	typed.Heuristic = func(vertex typedVertex) float64 { return vertex.Cff }
+
+	g, err := typed.Graph(typedVertexMapper, typedEdgeMapper)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	a, b := g.VertexMap()["a"], g.VertexMap()["b"]
+	if a.Cost(b) != 4 || b.Cost(a) != 4 {
+		t.Error("TypedGraph.Graph() failed to wire the typed cost function")
+	}
+	if a.Heuristic() != 1.5 {
+		t.Error("TypedGraph.Graph() failed to wire the typed heuristic function")
+	}
+	value, err := g.GetEdgeAttribute("b", "a", "k")
+	if err != nil || value != 4.0 {
+		t.Error("TypedGraph.Graph() failed to convert edge payloads to attributes")
+	}
+}
+
+func TestTypedGraphRoundTrip(t *testing.T) {
+	ok, g := dot.Parse([]byte(`digraph mixed { a -- [k=1] b; b -> [k=2] c; }`), false)
+	if !ok {
+		t.Error("Failed to parse mixed")
+		return
+	}
+	g.SetGraphAttribute("rankdir", "LR")
+	typed, err := dot.FromGraph(g, typedVertexMapper, typedEdgeMapper)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !typed.IsUndirectedEdge("a", "b") || typed.IsUndirectedEdge("b", "c") {
+		t.Error("FromGraph() didn't keep the direction of edges")
+	}
+	back, err := typed.Graph(typedVertexMapper, typedEdgeMapper)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !back.IsUndirectedEdge("b", "a") || back.IsUndirectedEdge("b", "c") {
+		t.Error("TypedGraph.Graph() didn't keep the direction of edges")
+	}
+	if !reflect.DeepEqual(back.GetGraphAttributes(), g.GetGraphAttributes()) {
+		t.Errorf("TypedGraph.Graph() wrote graph attributes %v, expected %v", back.GetGraphAttributes(),
+			g.GetGraphAttributes())
+	}
+}
+
+func TestIntAttribute(t *testing.T) {
+	attributes := map[string]interface{}{"k": 2.0, "w": 2.5, "label": "two", "big": math.Pow(2, 63)}
+	if value, err := dot.IntAttribute(attributes, "k"); err != nil || value != 2 {
+		t.Errorf("IntAttribute() = %v (%v), expected 2", value, err)
+	}
+	reasons := map[string]string{"w": "not an integer", "label": "not a number", "h": "not found", "big": "overflows"}
+	for key, reason := range reasons {
+		if _, err := dot.IntAttribute(attributes, key); err == nil || !strings.Contains(err.Error(), reason) {
+			t.Errorf("IntAttribute() of %v returned error %v, expected it to report %q", key, err, reason)
+		}
+	}
+}
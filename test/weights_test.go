@@ -0,0 +1,147 @@
+package dot_test
+
+import (
+	"testing"
+
+	"github.com/christat/dot"
+)
+
+func TestCostCacheInvalidation(t *testing.T) {
+	g := generateGraph()
+	g.CostKey = "k"
+	g.HeuristicKey = "h_pdb"
+	vertices := g.VertexMap()
+	if vertices["C"].Cost(vertices["t"]) != 2 || vertices["C"].Heuristic() != 10 {
+		t.Error("Cost() or Heuristic() returned an incorrect value")
+		return
+	}
+
+	g.SetEdgeAttribute("C", "t", false, "k", 4.5)
+	if vertices["C"].Cost(vertices["t"]) != 4.5 {
+		t.Error("SetEdgeAttribute() failed to invalidate the cached cost")
+	}
+	g.SetEdgeAttributes("C", "t", true, map[string]interface{}{"k": 6})
+	if vertices["C"].Cost(vertices["t"]) != 6 {
+		t.Error("SetEdgeAttributes() failed to invalidate the cached cost")
+	}
+	g.SetVertexAttribute("C", "h_pdb", 3)
+	if vertices["C"].Heuristic() != 3 {
+		t.Error("SetVertexAttribute() failed to invalidate the cached heuristic")
+	}
+
+	attributes, _ := g.GetEdgeAttributes("C", "t")
+	attributes["k"] = 8
+	g.InvalidateWeights()
+	if vertices["C"].Cost(vertices["t"]) != 8 {
+		t.Error("InvalidateWeights() failed to invalidate the cached cost")
+	}
+
+	g.CostKey = "h_cff"
+	if vertices["s"].Cost(vertices["A"]) != 2.0 {
+		t.Error("Cost() failed to follow a change of CostKey")
+	}
+}
+
+func TestDefaultWeights(t *testing.T) {
+	g := generateGraph()
+	g.CostKey = "k"
+	g.HeuristicKey = "h_ff"
+	vertices := g.VertexMap()
+	if vertices["s"].Cost(vertices["t"]) != 10e9 || vertices["s"].Heuristic() != 0 {
+		t.Error("Cost() or Heuristic() didn't fall back to the initial defaults")
+	}
+	g.SetDefaultCost(100)
+	g.SetDefaultHeuristic(-1)
+	if vertices["s"].Cost(vertices["t"]) != 100 || vertices["s"].Heuristic() != -1 {
+		t.Error("Cost() or Heuristic() ignored the configured defaults")
+	}
+	if g.Compact().Vertex("s").Cost(g.Compact().Vertex("t")) != 100 {
+		t.Error("CompactVertex.Cost() ignored the configured default cost")
+	}
+	if len(g.WeightErrors()) != 0 {
+		t.Error("WeightErrors() recorded errors outside of strict mode")
+	}
+}
+
+func TestStrictWeights(t *testing.T) {
+	g := generateGraph()
+	g.CostKey = "h_cff"
+	g.HeuristicKey = "name"
+	g.StrictWeights = true
+	vertices := g.VertexMap()
+
+	// string-encoded numbers are valid costs
+	if vertices["s"].Cost(vertices["A"]) != 2.0 {
+		t.Error("Cost() failed to read a string-encoded number")
+	}
+	vertices["s"].Cost(vertices["C"])
+	vertices["s"].Cost(vertices["C"])
+	vertices["s"].Heuristic()
+	errors := g.WeightErrors()
+	if len(errors) != 2 {
+		t.Errorf("WeightErrors() expected 2 errors, got %v", errors)
+		return
+	}
+	missing, ok := errors[0].(dot.WeightError)
+	if !ok || missing.Origin != "s" || missing.Target != "C" || missing.Value != nil {
+		t.Errorf("WeightErrors() reported an incorrect missing cost: %v", errors[0])
+	}
+	invalid, ok := errors[1].(dot.WeightError)
+	if !ok || invalid.Origin != "s" || invalid.Target != "" || invalid.Value != "Start" {
+		t.Errorf("WeightErrors() reported an incorrect invalid heuristic: %v", errors[1])
+	}
+
+	g.ClearWeightErrors()
+	if len(g.WeightErrors()) != 0 {
+		t.Error("ClearWeightErrors() failed to discard the recorded errors")
+	}
+}
+
+// uncachedWeights makes g compute costs and heuristics through the attribute getters, as Vertex did before caching.
+func uncachedWeights(g *dot.Graph) {
+	g.CostFunc = func(origin, target *dot.Vertex) float64 {
+		cost, err := g.GetEdgeAttribute(origin.Name(), target.Name(), "k")
+		if err != nil {
+			return 10e9
+		}
+		switch value := cost.(type) {
+		case int:
+			return float64(value)
+		case float64:
+			return value
+		}
+		return 10e9
+	}
+	g.HeuristicFunc = func(vertex *dot.Vertex) float64 {
+		heuristic, err := g.GetVertexAttribute(vertex.Name(), "h")
+		if err != nil {
+			return 0
+		}
+		switch value := heuristic.(type) {
+		case int:
+			return float64(value)
+		case float64:
+			return value
+		}
+		return 0
+	}
+}
+
+func benchmarkAStar(b *testing.B, g *dot.Graph) {
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := g.ShortestPath("r0c0", "r99c99", dot.PathOptions{Algorithm: dot.AStar}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAStarUncached(b *testing.B) {
+	g := gridGraph(b, 100)
+	uncachedWeights(g)
+	benchmarkAStar(b, g)
+}
+
+func BenchmarkAStarCached(b *testing.B) {
+	benchmarkAStar(b, gridGraph(b, 100))
+}
@@ -0,0 +1,125 @@
+package dot
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CycleError is returned by TopologicalSort when the graph is cyclic. Cycle lists the vertices of one cycle in
+// order, starting and ending with the same vertex.
+type CycleError struct {
+	Cycle []string
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("cycle found: %v", strings.Join(e.Cycle, " -> "))
+}
+
+// TopologicalSort returns the vertices of a digraph ordered so that every vertex precedes the targets of its edges,
+// using Kahn's algorithm. Whenever several vertices are ready, the lexically smallest one comes first, so the
+// ordering is deterministic. Edges declared with "--" are undirected and impose no order.
+// If the graph is cyclic, a *CycleError listing the vertices of one cycle is returned. Graphs whose Type is not
+// "digraph" are rejected.
+func (g *Graph) TopologicalSort() ([]string, error) {
+	if g.Type != "digraph" {
+		return nil, fmt.Errorf("TopologicalSort() of graph %v: graph type is %v, not digraph", g.Name, g.Type)
+	}
+	inDegrees := make(map[string]int, len(g.vertexMap))
+	predecessors := make(map[string][]string)
+	for name := range g.vertexMap {
+		inDegrees[name] = 0
+	}
+	for origin := range g.adjacencyMap {
+		inDegrees[origin] = 0
+	}
+	for origin, neighbors := range g.adjacencyMap {
+		for _, neighbor := range neighbors {
+			target := neighbor.Name()
+			if g.undirectedEdges[origin][target] {
+				continue
+			}
+			inDegrees[target]++
+			predecessors[target] = append(predecessors[target], origin)
+		}
+	}
+
+	ready := &nameHeap{}
+	for name, degree := range inDegrees {
+		if degree == 0 {
+			heap.Push(ready, name)
+		}
+	}
+	order := make([]string, 0, len(inDegrees))
+	for ready.Len() > 0 {
+		vertex := heap.Pop(ready).(string)
+		order = append(order, vertex)
+		for _, neighbor := range g.adjacencyMap[vertex] {
+			target := neighbor.Name()
+			if g.undirectedEdges[vertex][target] {
+				continue
+			}
+			inDegrees[target]--
+			if inDegrees[target] == 0 {
+				heap.Push(ready, target)
+			}
+		}
+	}
+	if len(order) < len(inDegrees) {
+		return nil, &CycleError{Cycle: findCycle(inDegrees, predecessors)}
+	}
+	return order, nil
+}
+
+// findCycle returns a cycle among the vertices left with a positive in-degree by Kahn's algorithm. Each of them
+// has a predecessor which is also left, so walking predecessors from any of them eventually repeats a vertex.
+func findCycle(inDegrees map[string]int, predecessors map[string][]string) []string {
+	var remaining []string
+	for name, degree := range inDegrees {
+		if degree > 0 {
+			remaining = append(remaining, name)
+		}
+	}
+	sort.Strings(remaining)
+
+	position := make(map[string]int)
+	var walk []string
+	for vertex := remaining[0]; ; {
+		if start, seen := position[vertex]; seen {
+			walk = append(walk[start:], vertex)
+			break
+		}
+		position[vertex] = len(walk)
+		walk = append(walk, vertex)
+		next := ""
+		for _, predecessor := range predecessors[vertex] {
+			if inDegrees[predecessor] > 0 && (next == "" || predecessor < next) {
+				next = predecessor
+			}
+		}
+		vertex = next
+	}
+	// predecessors were followed backwards, so reverse to obtain the direction of the edges
+	for i, j := 0, len(walk)-1; i < j; i, j = i+1, j-1 {
+		walk[i], walk[j] = walk[j], walk[i]
+	}
+	return walk
+}
+
+// nameHeap is a min-heap of vertex names.
+type nameHeap []string
+
+func (h nameHeap) Len() int { return len(h) }
+
+func (h nameHeap) Less(i, j int) bool { return h[i] < h[j] }
+
+func (h nameHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *nameHeap) Push(name interface{}) { *h = append(*h, name.(string)) }
+
+func (h *nameHeap) Pop() interface{} {
+	last := (*h)[len(*h)-1]
+	*h = (*h)[:len(*h)-1]
+	return last
+}
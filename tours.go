@@ -0,0 +1,287 @@
+package dot
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrNoEulerianPath is returned when no path traverses every edge of the graph exactly once.
+	ErrNoEulerianPath = errors.New("no eulerian path found")
+	// ErrNoHamiltonianPath is returned when no path visits every vertex of the graph exactly once.
+	ErrNoHamiltonianPath = errors.New("no hamiltonian path found")
+	// ErrSearchLimit is returned when a search is abandoned because of its time or size limit.
+	ErrSearchLimit = errors.New("search limit exceeded")
+)
+
+// EulerianPath returns a sequence of vertices traversing every edge of the graph exactly once, built with
+// Hierholzer's algorithm. If the graph has an Eulerian circuit, the path is a circuit starting and ending at its
+// alphabetically first vertex with edges. In graphs of type "graph" edges are undirected; in digraphs every stored
+// edge is directed, so edges declared with "--" must be traversed once in each direction.
+// If there is no such path, ErrNoEulerianPath is returned. A graph without edges yields an empty path.
+func (g *Graph) EulerianPath() ([]string, error) {
+	tour := g.eulerTour()
+	start, ok := tour.start(false)
+	if !ok {
+		return nil, ErrNoEulerianPath
+	}
+	return tour.walk(start)
+}
+
+// EulerianCircuit returns a closed sequence of vertices, starting and ending at the alphabetically first vertex with
+// edges, traversing every edge of the graph exactly once. See EulerianPath. If there is no such circuit,
+// ErrNoEulerianPath is returned.
+func (g *Graph) EulerianCircuit() ([]string, error) {
+	tour := g.eulerTour()
+	start, ok := tour.start(true)
+	if !ok {
+		return nil, ErrNoEulerianPath
+	}
+	return tour.walk(start)
+}
+
+// eulerTour holds the edges of a graph as arcs. An undirected edge is stored as two arcs sharing the same edge ID,
+// so that traversing either one uses it up, except for self loops which are stored as a single arc.
+type eulerTour struct {
+	names      []string
+	arcs       [][]halfEdge
+	edges      int
+	undirected bool
+}
+
+func (g *Graph) eulerTour() *eulerTour {
+	if g.Type == "graph" {
+		view := g.undirectedView()
+		tour := &eulerTour{names: view.names, arcs: view.adjacency, edges: view.edges, undirected: true}
+		for v, loops := range view.loops {
+			for ; loops > 0; loops-- {
+				tour.arcs[v] = append(tour.arcs[v], halfEdge{target: v, edge: tour.edges})
+				tour.edges++
+			}
+		}
+		return tour
+	}
+	c := g.topology()
+	tour := &eulerTour{names: c.names, arcs: make([][]halfEdge, c.Order())}
+	for v := 0; v < c.Order(); v++ {
+		for _, w := range c.Neighbors(v) {
+			tour.arcs[v] = append(tour.arcs[v], halfEdge{target: w, edge: tour.edges})
+			tour.edges++
+		}
+	}
+	return tour
+}
+
+// start returns the vertex an Eulerian path (or circuit, if circuit is true) must start from, and whether the
+// degrees of the vertices allow one. Connectivity is checked by walk.
+func (t *eulerTour) start(circuit bool) (int, bool) {
+	first, odd := -1, []int(nil)
+	balance := make([]int, len(t.names))
+	for v, arcs := range t.arcs {
+		for _, arc := range arcs {
+			balance[v]++
+			if !t.undirected {
+				balance[arc.target]--
+			} else if arc.target == v {
+				// a self loop counts twice towards the degree
+				balance[v]++
+			}
+		}
+		if first == -1 && len(arcs) > 0 {
+			first = v
+		}
+	}
+	if first == -1 {
+		return -1, true
+	}
+	for v, value := range balance {
+		if t.undirected && value%2 != 0 {
+			odd = append(odd, v)
+		}
+		if !t.undirected && value != 0 {
+			// the path starts at the vertex with an extra outgoing edge
+			if value > 1 || value < -1 {
+				return -1, false
+			}
+			odd = append(odd, v)
+			if value == 1 {
+				odd[0], odd[len(odd)-1] = odd[len(odd)-1], odd[0]
+			}
+		}
+	}
+	switch {
+	case len(odd) == 0:
+		return first, true
+	case len(odd) == 2 && !circuit:
+		return odd[0], true
+	}
+	return -1, false
+}
+
+// walk builds the path from start with Hierholzer's algorithm, failing if some edge can't be reached.
+func (t *eulerTour) walk(start int) ([]string, error) {
+	if start == -1 {
+		return []string{}, nil
+	}
+	used := make([]bool, t.edges)
+	next := make([]int, len(t.names))
+	var path []int
+	for stack := []int{start}; len(stack) > 0; {
+		v := stack[len(stack)-1]
+		for next[v] < len(t.arcs[v]) && used[t.arcs[v][next[v]].edge] {
+			next[v]++
+		}
+		if next[v] == len(t.arcs[v]) {
+			path = append(path, v)
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		arc := t.arcs[v][next[v]]
+		used[arc.edge] = true
+		stack = append(stack, arc.target)
+	}
+	if len(path) != t.edges+1 {
+		// the edges are split among several components
+		return nil, ErrNoEulerianPath
+	}
+	names := make([]string, len(path))
+	// vertices are appended once their edges are exhausted, i.e. in reverse order
+	for i, v := range path {
+		names[len(path)-1-i] = t.names[v]
+	}
+	return names, nil
+}
+
+// HamiltonianOptions configures a HamiltonianPath search, which takes exponential time in the worst case.
+type HamiltonianOptions struct {
+	// Cycle requires the path to return to its first vertex through an edge.
+	Cycle bool
+	// Timeout, if positive, abandons the search after that duration.
+	Timeout time.Duration
+	// MaxVertices, if positive, rejects graphs with more vertices without searching.
+	MaxVertices int
+}
+
+// HamiltonianPath searches, by backtracking, a sequence of vertices visiting every vertex of the graph exactly once
+// through its edges. Candidates are tried starting with the vertex with the fewest unvisited neighbors, which finds
+// paths quickly in most graphs. With opts.Cycle, the path is closed, ending with its first vertex; as it can't use an
+// edge twice, a cycle through two vertices needs two edges between them.
+// ErrNoHamiltonianPath is returned if there is no such path, and ErrSearchLimit if a limit of opts is reached.
+func (g *Graph) HamiltonianPath(opts HamiltonianOptions) ([]string, error) {
+	c := g.topology()
+	if opts.MaxVertices > 0 && c.Order() > opts.MaxVertices {
+		return nil, fmt.Errorf("HamiltonianPath() of graph %v with %v vertices: %w", g.Name, c.Order(), ErrSearchLimit)
+	}
+	if c.Order() == 0 || opts.Cycle && c.Order() == 2 && len(g.undirectedView().adjacency[0]) < 2 {
+		return nil, ErrNoHamiltonianPath
+	}
+	search := &hamiltonianSearch{
+		graph:     c,
+		neighbors: make([][]int, c.Order()),
+		visited:   make([]bool, c.Order()),
+		cycle:     opts.Cycle,
+	}
+	if opts.Timeout > 0 {
+		search.deadline = time.Now().Add(opts.Timeout)
+	}
+	for v := range search.neighbors {
+		for _, w := range uniqueInts(c.Neighbors(v)) {
+			if w != v {
+				search.neighbors[v] = append(search.neighbors[v], w)
+			}
+		}
+	}
+
+	starts := c.Order()
+	if opts.Cycle {
+		// a cycle goes through every vertex, so any of them can start it
+		starts = 1
+	}
+	for start := 0; start < starts; start++ {
+		search.path = append(search.path[:0], start)
+		search.visited[start] = true
+		found := search.extend()
+		search.visited[start] = false
+		if search.exceeded {
+			return nil, ErrSearchLimit
+		}
+		if found {
+			names := make([]string, len(search.path))
+			for i, v := range search.path {
+				names[i] = c.names[v]
+			}
+			if opts.Cycle {
+				names = append(names, names[0])
+			}
+			return names, nil
+		}
+	}
+	return nil, ErrNoHamiltonianPath
+}
+
+type hamiltonianSearch struct {
+	graph     *CompactGraph
+	neighbors [][]int
+	visited   []bool
+	path      []int
+	cycle     bool
+	deadline  time.Time
+	steps     int
+	exceeded  bool
+}
+
+// extend tries to complete the current path, returning true if it succeeded.
+func (s *hamiltonianSearch) extend() bool {
+	s.steps++
+	if !s.deadline.IsZero() && s.steps%1024 == 0 && time.Now().After(s.deadline) {
+		s.exceeded = true
+	}
+	if s.exceeded {
+		return false
+	}
+	last := s.path[len(s.path)-1]
+	if len(s.path) == len(s.visited) {
+		return !s.cycle || s.adjacent(last, s.path[0])
+	}
+
+	candidates := make([]int, 0, len(s.neighbors[last]))
+	for _, w := range s.neighbors[last] {
+		if !s.visited[w] {
+			candidates = append(candidates, w)
+		}
+	}
+	remaining := make(map[int]int, len(candidates))
+	for _, w := range candidates {
+		for _, u := range s.neighbors[w] {
+			if !s.visited[u] {
+				remaining[w]++
+			}
+		}
+	}
+	// insertion sort keeps ties in ID order
+	for i := 1; i < len(candidates); i++ {
+		for j := i; j > 0 && remaining[candidates[j]] < remaining[candidates[j-1]]; j-- {
+			candidates[j], candidates[j-1] = candidates[j-1], candidates[j]
+		}
+	}
+	for _, w := range candidates {
+		s.visited[w] = true
+		s.path = append(s.path, w)
+		if s.extend() {
+			return true
+		}
+		s.path = s.path[:len(s.path)-1]
+		s.visited[w] = false
+	}
+	return false
+}
+
+func (s *hamiltonianSearch) adjacent(v int, w int) bool {
+	for _, u := range s.neighbors[v] {
+		if u == w {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package dot
+
+import "fmt"
+
+// TransitiveReduction returns a new digraph with the same reachability as g and as few edges as possible: every
+// edge origin -> target is dropped if target can also be reached from origin through other edges, and repeated
+// edges are kept once. Vertex attributes and the attributes of the retained edges are kept.
+// Edges declared with "--" impose no dependency and are kept as they are. The graph must be an acyclic digraph;
+// otherwise the error of TopologicalSort is returned.
+func (g *Graph) TransitiveReduction() (*Graph, error) {
+	order, err := g.TopologicalSort()
+	if err != nil {
+		return nil, err
+	}
+	c := g.topology()
+	reach := g.reachability(c, order)
+	redundant := make(map[Edge]bool)
+	for v := 0; v < c.Order(); v++ {
+		successors := g.directedSuccessors(c, v)
+		for _, w := range successors {
+			for _, u := range successors {
+				if u != w && reach[u].has(w) {
+					redundant[Edge{Origin: c.names[v], Target: c.names[w]}] = true
+					break
+				}
+			}
+		}
+	}
+	reduced := g.subgraph(
+		func(string) bool { return true },
+		func(origin, target string) bool { return !redundant[Edge{Origin: origin, Target: target}] })
+	for origin, neighbors := range reduced.adjacencyMap {
+		kept := neighbors[:0]
+		seen := make(map[string]bool)
+		for _, neighbor := range neighbors {
+			target := neighbor.Name()
+			if !reduced.undirectedEdges[origin][target] && seen[target] {
+				continue
+			}
+			seen[target] = true
+			kept = append(kept, neighbor)
+		}
+		reduced.adjacencyMap[origin] = kept
+	}
+	return reduced, nil
+}
+
+// TransitiveClosure returns a new digraph holding every edge of g, plus an edge origin -> target without attributes
+// for every vertex target reachable from origin which is not already adjacent to it. A vertex on a cycle reaches
+// itself. Edges declared with "--" impose no dependency and are not followed. Graphs whose Type is not "digraph"
+// are rejected.
+func (g *Graph) TransitiveClosure() (*Graph, error) {
+	if g.Type != "digraph" {
+		return nil, fmt.Errorf("TransitiveClosure() of graph %v: graph type is %v, not digraph", g.Name, g.Type)
+	}
+	c := g.topology()
+	closure := g.Clone()
+	for v := 0; v < c.Order(); v++ {
+		adjacent := make(map[int]bool)
+		for _, w := range g.directedSuccessors(c, v) {
+			adjacent[w] = true
+		}
+		reached := make([]bool, c.Order())
+		queue := g.directedSuccessors(c, v)
+		for _, w := range queue {
+			reached[w] = true
+		}
+		for ; len(queue) > 0; queue = queue[1:] {
+			for _, w := range g.directedSuccessors(c, queue[0]) {
+				if !reached[w] {
+					reached[w] = true
+					queue = append(queue, w)
+				}
+			}
+		}
+		origin := c.names[v]
+		for w, isReached := range reached {
+			if isReached && !adjacent[w] {
+				closure.adjacencyMap[origin] = append(closure.adjacencyMap[origin], closure.fetchOrCreateVertex(c.names[w]))
+			}
+		}
+	}
+	return closure, nil
+}
+
+// directedSuccessors returns the distinct targets of the edges leaving v which were not declared with "--".
+func (g *Graph) directedSuccessors(c *CompactGraph, v int) []int {
+	var successors []int
+	for _, w := range uniqueInts(c.Neighbors(v)) {
+		if !g.undirectedEdges[c.names[v]][c.names[w]] {
+			successors = append(successors, w)
+		}
+	}
+	return successors
+}
+
+// reachability returns, for every vertex of an acyclic g, the set of vertices reachable from it through directed
+// edges, given the topological order of its vertices.
+func (g *Graph) reachability(c *CompactGraph, order []string) []bitset {
+	reach := make([]bitset, c.Order())
+	for i := len(order) - 1; i >= 0; i-- {
+		v := c.index[order[i]]
+		reach[v] = newBitset(c.Order())
+		for _, w := range g.directedSuccessors(c, v) {
+			reach[v].add(w)
+			reach[v].union(reach[w])
+		}
+	}
+	return reach
+}
+
+// bitset is a set of vertex IDs.
+type bitset []uint64
+
+func newBitset(size int) bitset {
+	return make(bitset, (size+63)/64)
+}
+
+func (b bitset) add(id int) {
+	b[id/64] |= 1 << uint(id%64)
+}
+
+func (b bitset) has(id int) bool {
+	return b[id/64]&(1<<uint(id%64)) != 0
+}
+
+func (b bitset) union(other bitset) {
+	for i := range b {
+		b[i] |= other[i]
+	}
+}
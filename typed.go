@@ -0,0 +1,219 @@
+package dot
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/christat/search"
+)
+
+// TypedGraph is a graph whose vertices and edges carry payloads of user defined types V and E, instead of the
+// map[string]interface{} attributes of Graph. Use FromGraph and TypedGraph.Graph, along with an AttributeMapper
+// for each payload type, to convert from and to DOT attributes.
+type TypedGraph[V, E any] struct {
+	Name string
+	Type string
+
+	// Cost and Heuristic, if set, are used as CostFunc and HeuristicFunc of the graphs built by TypedGraph.Graph,
+	// allowing search on typed payloads.
+	Cost      func(edge E) float64
+	Heuristic func(vertex V) float64
+
+	// GraphAttributes holds the attributes of the graph itself, which are copied as they are by FromGraph and
+	// TypedGraph.Graph.
+	GraphAttributes map[string]interface{}
+
+	vertices     map[string]V
+	adjacencyMap map[string][]string
+	edges        map[string]map[string]E
+	// undirected marks both directions of the edges added with isDirectional set to false.
+	undirected map[string]map[string]bool
+}
+
+// AttributeMapper converts payloads of type T from and to DOT attribute maps.
+// FromAttributes receives a nil map for vertices or edges without attributes.
+type AttributeMapper[T any] struct {
+	FromAttributes func(attributes map[string]interface{}) (T, error)
+	ToAttributes   func(payload T) (map[string]interface{}, error)
+}
+
+// NewTypedGraph creates and returns a pointer to a new, empty TypedGraph.
+func NewTypedGraph[V, E any]() *TypedGraph[V, E] {
+	return &TypedGraph[V, E]{
+		GraphAttributes: make(map[string]interface{}),
+		vertices:        make(map[string]V),
+		adjacencyMap:    make(map[string][]string),
+		edges:           make(map[string]map[string]E),
+		undirected:      make(map[string]map[string]bool),
+	}
+}
+
+// FromGraph builds a TypedGraph from g, converting vertex and edge attributes through the FromAttributes function
+// of the given mappers. The first conversion error aborts the process. Edges declared with "--" are added as
+// undirected edges, and graph attributes are copied.
+func FromGraph[V, E any](g *Graph, vertexMapper AttributeMapper[V], edgeMapper AttributeMapper[E]) (*TypedGraph[V, E], error) {
+	t := NewTypedGraph[V, E]()
+	t.Name = g.Name
+	t.Type = g.Type
+	t.GraphAttributes = copyAttributes(g.graphAttributes)
+
+	names := make([]string, 0, len(g.vertexMap))
+	for name := range g.vertexMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		payload, err := vertexMapper.FromAttributes(g.vertexAttributes[name])
+		if err != nil {
+			return nil, fmt.Errorf("FromGraph() of vertex %v: %v", name, err)
+		}
+		t.AddVertex(name, payload)
+	}
+	for _, origin := range names {
+		for _, neighbor := range g.adjacencyMap[origin] {
+			target := neighbor.Name()
+			payload, err := edgeMapper.FromAttributes(g.edgeAttributes[origin][target])
+			if err != nil {
+				return nil, fmt.Errorf("FromGraph() of edge %v -> %v: %v", origin, target, err)
+			}
+			t.AddEdge(origin, target, !g.IsUndirectedEdge(origin, target), payload)
+		}
+	}
+	return t, nil
+}
+
+// Graph builds a Graph from t, converting payloads to attributes through the ToAttributes function of the given
+// mappers. The first conversion error aborts the process. If t.Cost or t.Heuristic are set, they are wired as
+// CostFunc and HeuristicFunc of the new graph.
+func (t *TypedGraph[V, E]) Graph(vertexMapper AttributeMapper[V], edgeMapper AttributeMapper[E]) (*Graph, error) {
+	g := NewGraph()
+	g.Name = t.Name
+	g.Type = t.Type
+	g.graphAttributes = copyAttributes(t.GraphAttributes)
+
+	names := t.Vertices()
+	for _, name := range names {
+		g.fetchOrCreateVertex(name)
+		attributes, err := vertexMapper.ToAttributes(t.vertices[name])
+		if err != nil {
+			return nil, fmt.Errorf("Graph() of vertex %v: %v", name, err)
+		}
+		if len(attributes) > 0 {
+			g.vertexAttributes[name] = attributes
+		}
+	}
+	for _, origin := range names {
+		targets, exists := t.adjacencyMap[origin]
+		if !exists {
+			continue
+		}
+		neighbors := make([]search.State, len(targets))
+		for i, target := range targets {
+			neighbors[i] = g.fetchOrCreateVertex(target)
+			attributes, err := edgeMapper.ToAttributes(t.edges[origin][target])
+			if err != nil {
+				return nil, fmt.Errorf("Graph() of edge %v -> %v: %v", origin, target, err)
+			}
+			g.SetEdgeAttributes(origin, target, true, attributes)
+			if t.undirected[origin][target] {
+				g.setUndirected(origin, target)
+			}
+		}
+		g.adjacencyMap[origin] = neighbors
+	}
+
+	if t.Cost != nil {
+		g.CostFunc = func(origin, target *Vertex) float64 {
+			edge, exists := t.Edge(origin.Name(), target.Name())
+			if !exists {
+				return g.DefaultCost()
+			}
+			return t.Cost(edge)
+		}
+	}
+	if t.Heuristic != nil {
+		g.HeuristicFunc = func(vertex *Vertex) float64 {
+			payload, exists := t.Vertex(vertex.Name())
+			if !exists {
+				return g.DefaultHeuristic()
+			}
+			return t.Heuristic(payload)
+		}
+	}
+	return g, nil
+}
+
+// AddVertex adds a vertex to the graph, or replaces the payload of an existing one.
+func (t *TypedGraph[V, E]) AddVertex(name string, payload V) {
+	t.vertices[name] = payload
+}
+
+// Vertex returns the payload of the named vertex, and whether the vertex exists.
+func (t *TypedGraph[V, E]) Vertex(name string) (V, bool) {
+	payload, exists := t.vertices[name]
+	return payload, exists
+}
+
+// Vertices returns the names of all vertices of the graph, sorted alphabetically.
+func (t *TypedGraph[V, E]) Vertices() []string {
+	names := make([]string, 0, len(t.vertices))
+	for name := range t.vertices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AddEdge adds the edge origin -> target to the graph, or replaces the payload of an existing one.
+// If isDirectional is false, the edge target -> origin is added with the same payload, and both are marked as a
+// single undirected edge, like edges declared with "--". Missing vertices are created with the zero value of V as
+// payload.
+func (t *TypedGraph[V, E]) AddEdge(origin string, target string, isDirectional bool, payload E) {
+	t.addEdge(origin, target, payload)
+	if isDirectional {
+		delete(t.undirected[origin], target)
+		delete(t.undirected[target], origin)
+		return
+	}
+	t.addEdge(target, origin, payload)
+	for _, edge := range [][2]string{{origin, target}, {target, origin}} {
+		if t.undirected[edge[0]] == nil {
+			t.undirected[edge[0]] = make(map[string]bool)
+		}
+		t.undirected[edge[0]][edge[1]] = true
+	}
+}
+
+// IsUndirectedEdge returns true if the edge origin -> target was added as an undirected edge. See AddEdge.
+func (t *TypedGraph[V, E]) IsUndirectedEdge(origin string, target string) bool {
+	return t.undirected[origin][target]
+}
+
+func (t *TypedGraph[V, E]) addEdge(origin string, target string, payload E) {
+	for _, name := range []string{origin, target} {
+		if _, exists := t.vertices[name]; !exists {
+			var zero V
+			t.vertices[name] = zero
+		}
+	}
+	if _, exists := t.edges[origin]; !exists {
+		t.edges[origin] = make(map[string]E)
+	}
+	if _, exists := t.edges[origin][target]; !exists {
+		t.adjacencyMap[origin] = append(t.adjacencyMap[origin], target)
+	}
+	t.edges[origin][target] = payload
+}
+
+// Edge returns the payload of the edge origin -> target, and whether the edge exists.
+func (t *TypedGraph[V, E]) Edge(origin string, target string) (E, bool) {
+	payload, exists := t.edges[origin][target]
+	return payload, exists
+}
+
+// Neighbors returns the names of the vertices adjacent to the named vertex, in insertion order.
+func (t *TypedGraph[V, E]) Neighbors(name string) []string {
+	neighbors := make([]string, len(t.adjacencyMap[name]))
+	copy(neighbors, t.adjacencyMap[name])
+	return neighbors
+}
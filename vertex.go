@@ -18,9 +18,15 @@ func (v *Vertex) Name() string {
 }
 
 // Equals implements the search.State interface, comparing two instances of a Vertex by name (by dot standards,
-// they should be unique).
+// they should be unique). A ProfiledVertex is equal to the Vertex it wraps; any other state is not.
 func (v *Vertex) Equals(other search.State) bool {
-	return v.name == other.(*Vertex).name
+	switch o := other.(type) {
+	case *Vertex:
+		return v.name == o.name
+	case *ProfiledVertex:
+		return v.name == o.name
+	}
+	return false
 }
 
 // Neighbors allows to obtain a map of adjacent vertices to the caller.
@@ -39,50 +45,17 @@ const defaultHeuristic = 0
 
 
 // Cost relies on the underlying graph structure to obtain either a cost function to traverse from v to target,
-// or alternatively a cost key if the cost is coded into the graph description. Alternatively, it returns a
-// default cost of 10e9 as a measure of caution.
+// or alternatively a cost key if the cost is coded into the graph description. Alternatively, it returns the
+// default cost of the graph (10e9 unless changed through Graph.SetDefaultCost) as a measure of caution.
+// Costs read through the cost key are cached by the graph, see Graph.InvalidateWeights. If Graph.StrictWeights is set,
+// edges lacking a valid cost are reported through Graph.WeightErrors.
 func (v *Vertex) Cost(target search.State) float64 {
-	if v.graph.CostFunc != nil {
-		return v.graph.CostFunc(v, target.(*Vertex))
-	}
-	if v.graph.CostKey != "" {
-		cost, err := v.graph.GetEdgeAttribute(v.name, target.(*Vertex).name, v.graph.CostKey)
-		if err != nil {
-			return defaultCost
-		}
-		floatCost, ok := cost.(float64)
-		if !ok {
-			intCost, ok := cost.(int)
-			if !ok {
-				return defaultCost
-			}
-			return float64(intCost)
-		}
-		return floatCost
-	}
-	return defaultCost
+	return v.graph.cost(v.graph.CostKey, v.graph.CostFunc, v, target.(*Vertex))
 }
 
 // Heuristic, similarly to the Cost method, relies on either a function or a key passed as an attribute of the
-// underlying graph. As a fallback, a heuristic value of 0 is returned.
+// underlying graph. As a fallback, the default heuristic of the graph (0 unless changed through
+// Graph.SetDefaultHeuristic) is returned.
 func (v *Vertex) Heuristic() float64 {
-	if v.graph.HeuristicFunc != nil {
-		return v.graph.HeuristicFunc(v)
-	}
-	if v.graph.HeuristicKey != "" {
-		heuristic, err := v.graph.GetVertexAttribute(v.name, v.graph.HeuristicKey)
-		if err != nil {
-			return defaultHeuristic
-		}
-		floatHeuristic, ok := heuristic.(float64)
-		if !ok {
-			intHeuristic, ok := heuristic.(int)
-			if !ok {
-				return defaultHeuristic
-			}
-			return float64(intHeuristic)
-		}
-		return floatHeuristic
-	}
-	return defaultHeuristic
+	return v.graph.heuristic(v.graph.HeuristicKey, v.graph.HeuristicFunc, v)
 }
@@ -0,0 +1,290 @@
+package dot
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// weightCache stores, per attribute key, the numeric values of that attribute for every edge and vertex.
+// Tables are built on first use and dropped whenever an attribute with their key is set through the Graph API,
+// so that Vertex.Cost and Vertex.Heuristic only need a single lookup per call.
+//
+// Graphs are single-writer: attributes must not be modified while the graph is being searched, but any number of
+// searches may run concurrently. Tables are therefore built from the attribute maps without locking, and published
+// as immutable weightTables which lookups load atomically; mutex only serializes the writers of tables and errors.
+type weightCache struct {
+	mutex  sync.RWMutex
+	tables atomic.Value // *weightTables
+
+	// errors collects the WeightErrors found in strict mode. reported avoids recording the same error twice.
+	errors   []error
+	reported map[WeightError]bool
+}
+
+// weightTables is a snapshot of the cached tables. It is never modified once stored in a weightCache.
+type weightTables struct {
+	edges    map[string]map[string]map[string]float64
+	vertices map[string]map[string]float64
+}
+
+// load returns the current tables, which are empty until the first one is built.
+func (c *weightCache) load() *weightTables {
+	if tables, ok := c.tables.Load().(*weightTables); ok {
+		return tables
+	}
+	return &weightTables{}
+}
+
+// update stores a copy of the current tables modified by change. Callers must hold mutex.
+func (c *weightCache) update(change func(tables *weightTables)) {
+	current := c.load()
+	tables := &weightTables{
+		edges:    make(map[string]map[string]map[string]float64, len(current.edges)+1),
+		vertices: make(map[string]map[string]float64, len(current.vertices)+1),
+	}
+	for key, table := range current.edges {
+		tables.edges[key] = table
+	}
+	for key, table := range current.vertices {
+		tables.vertices[key] = table
+	}
+	change(tables)
+	c.tables.Store(tables)
+}
+
+// WeightError describes a cost or heuristic which could not be read from the attributes of a graph.
+// Target is empty for heuristics, and Value is nil if the attribute is missing.
+type WeightError struct {
+	Key    string
+	Origin string
+	Target string
+	Value  interface{}
+}
+
+func (e WeightError) Error() string {
+	element := fmt.Sprintf("vertex %v", e.Origin)
+	if e.Target != "" {
+		element = fmt.Sprintf("edge %v -> %v", e.Origin, e.Target)
+	}
+	if e.Value == nil {
+		return fmt.Sprintf("%v: attribute %v not found", element, e.Key)
+	}
+	return fmt.Sprintf("%v: attribute %v has non-numeric value %v", element, e.Key, e.Value)
+}
+
+// SetDefaultCost sets the cost returned by Vertex.Cost for edges without a valid cost. The initial default is 10e9.
+func (g *Graph) SetDefaultCost(cost float64) {
+	g.costFallback = &cost
+}
+
+// DefaultCost returns the cost used for edges without a valid cost.
+func (g *Graph) DefaultCost() float64 {
+	if g.costFallback != nil {
+		return *g.costFallback
+	}
+	return defaultCost
+}
+
+// SetDefaultHeuristic sets the value returned by Vertex.Heuristic for vertices without a valid heuristic.
+// The initial default is 0.
+func (g *Graph) SetDefaultHeuristic(heuristic float64) {
+	g.heuristicFallback = &heuristic
+}
+
+// DefaultHeuristic returns the heuristic used for vertices without a valid heuristic.
+func (g *Graph) DefaultHeuristic() float64 {
+	if g.heuristicFallback != nil {
+		return *g.heuristicFallback
+	}
+	return defaultHeuristic
+}
+
+// WeightErrors returns the errors (of type WeightError) recorded while StrictWeights was set, in the order they
+// were found. Each missing or invalid cost or heuristic is only reported once until ClearWeightErrors is called.
+func (g *Graph) WeightErrors() []error {
+	cache := g.weights()
+	cache.mutex.RLock()
+	defer cache.mutex.RUnlock()
+	errors := make([]error, len(cache.errors))
+	copy(errors, cache.errors)
+	return errors
+}
+
+// ClearWeightErrors discards all recorded weight errors.
+func (g *Graph) ClearWeightErrors() {
+	cache := g.weights()
+	cache.mutex.Lock()
+	cache.errors = nil
+	cache.reported = nil
+	cache.mutex.Unlock()
+}
+
+// missingCost returns the default cost for origin -> target, recording the reason in strict mode.
+func (g *Graph) missingCost(key string, origin string, target string) float64 {
+	if g.StrictWeights {
+		attribute, _ := g.GetEdgeAttribute(origin, target, key)
+		g.reportWeightError(WeightError{Key: key, Origin: origin, Target: target, Value: attribute})
+	}
+	return g.DefaultCost()
+}
+
+// missingHeuristic returns the default heuristic for vertex, recording the reason in strict mode.
+func (g *Graph) missingHeuristic(key string, vertex string) float64 {
+	if g.StrictWeights {
+		attribute, _ := g.GetVertexAttribute(vertex, key)
+		g.reportWeightError(WeightError{Key: key, Origin: vertex, Value: attribute})
+	}
+	return g.DefaultHeuristic()
+}
+
+func (g *Graph) reportWeightError(err WeightError) {
+	// values are formatted so that the error can be used as a map key regardless of their type
+	key := err
+	if key.Value != nil {
+		key.Value = fmt.Sprint(key.Value)
+	}
+	cache := g.weights()
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if cache.reported[key] {
+		return
+	}
+	if cache.reported == nil {
+		cache.reported = make(map[WeightError]bool)
+	}
+	cache.reported[key] = true
+	cache.errors = append(cache.errors, err)
+}
+
+// InvalidateWeights drops all cached cost and heuristic tables. It is only needed after modifying attribute maps
+// obtained from GetVertexAttributes or GetEdgeAttributes directly; the setters of Graph invalidate the cache themselves.
+func (g *Graph) InvalidateWeights() {
+	cache := g.weights()
+	cache.mutex.Lock()
+	cache.tables.Store(&weightTables{})
+	cache.mutex.Unlock()
+}
+
+// edgeWeight returns the numeric value of attribute key of the edge origin -> target, if it has one.
+func (g *Graph) edgeWeight(key string, origin string, target string) (float64, bool) {
+	table, exists := g.weights().load().edges[key]
+	if !exists {
+		table = g.buildEdgeTable(key)
+	}
+	weight, exists := table[origin][target]
+	return weight, exists
+}
+
+// vertexWeight returns the numeric value of attribute key of vertex, if it has one.
+func (g *Graph) vertexWeight(key string, vertex string) (float64, bool) {
+	table, exists := g.weights().load().vertices[key]
+	if !exists {
+		table = g.buildVertexTable(key)
+	}
+	weight, exists := table[vertex]
+	return weight, exists
+}
+
+// buildEdgeTable reads the edge attributes without holding mutex, which the single-writer contract allows.
+func (g *Graph) buildEdgeTable(key string) map[string]map[string]float64 {
+	table := make(map[string]map[string]float64, len(g.edgeAttributes))
+	for origin, targets := range g.edgeAttributes {
+		for target, attributes := range targets {
+			if weight, ok := toFloat64(attributes[key]); ok {
+				if table[origin] == nil {
+					table[origin] = make(map[string]float64)
+				}
+				table[origin][target] = weight
+			}
+		}
+	}
+	cache := g.weights()
+	cache.mutex.Lock()
+	cache.update(func(tables *weightTables) { tables.edges[key] = table })
+	cache.mutex.Unlock()
+	return table
+}
+
+// buildVertexTable reads the vertex attributes without holding mutex, as buildEdgeTable does.
+func (g *Graph) buildVertexTable(key string) map[string]float64 {
+	table := make(map[string]float64, len(g.vertexAttributes))
+	for vertex, attributes := range g.vertexAttributes {
+		if weight, ok := toFloat64(attributes[key]); ok {
+			table[vertex] = weight
+		}
+	}
+	cache := g.weights()
+	cache.mutex.Lock()
+	cache.update(func(tables *weightTables) { tables.vertices[key] = table })
+	cache.mutex.Unlock()
+	return table
+}
+
+// invalidateEdgeWeights drops the cached edge table of key. An empty key drops all edge tables.
+func (g *Graph) invalidateEdgeWeights(key string) {
+	cache := g.weights()
+	cache.mutex.Lock()
+	cache.update(func(tables *weightTables) {
+		if key == "" {
+			tables.edges = nil
+		} else {
+			delete(tables.edges, key)
+		}
+	})
+	cache.mutex.Unlock()
+}
+
+// invalidateVertexWeights drops the cached vertex table of key. An empty key drops all vertex tables.
+func (g *Graph) invalidateVertexWeights(key string) {
+	cache := g.weights()
+	cache.mutex.Lock()
+	cache.update(func(tables *weightTables) {
+		if key == "" {
+			tables.vertices = nil
+		} else {
+			delete(tables.vertices, key)
+		}
+	})
+	cache.mutex.Unlock()
+}
+
+// weights returns the weight cache of the graph, creating it if needed so that zero value Graphs can be used.
+func (g *Graph) weights() *weightCache {
+	g.weightsOnce.Do(func() {
+		if g.weightCache == nil {
+			g.weightCache = new(weightCache)
+		}
+	})
+	return g.weightCache
+}
+
+// cost evaluates the cost of origin -> target through costFunc or, if nil, the numeric attribute costKey.
+func (g *Graph) cost(costKey string, costFunc func(origin, target *Vertex) float64, origin, target *Vertex) float64 {
+	if costFunc != nil {
+		return costFunc(origin, target)
+	}
+	if costKey != "" {
+		cost, ok := g.edgeWeight(costKey, origin.name, target.name)
+		if !ok {
+			return g.missingCost(costKey, origin.name, target.name)
+		}
+		return cost
+	}
+	return g.DefaultCost()
+}
+
+// heuristic evaluates the heuristic of vertex through heuristicFunc or, if nil, the numeric attribute heuristicKey.
+func (g *Graph) heuristic(heuristicKey string, heuristicFunc func(vertex *Vertex) float64, vertex *Vertex) float64 {
+	if heuristicFunc != nil {
+		return heuristicFunc(vertex)
+	}
+	if heuristicKey != "" {
+		heuristic, ok := g.vertexWeight(heuristicKey, vertex.name)
+		if !ok {
+			return g.missingHeuristic(heuristicKey, vertex.name)
+		}
+		return heuristic
+	}
+	return g.DefaultHeuristic()
+}
@@ -0,0 +1,292 @@
+package dot
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	plainIDRe   = regexp.MustCompile("^[a-zA-Z_][a-zA-Z0-9_]*$")
+	numeralIDRe = regexp.MustCompile(`^-?(\.[0-9]+|[0-9]+(\.[0-9]*)?)$`)
+	keywordIDs  = map[string]bool{"node": true, "edge": true, "graph": true, "digraph": true, "subgraph": true, "strict": true}
+
+	parsableIDRe        = regexp.MustCompile("^[[:alnum:]]+$")
+	parsableAttributeRe = regexp.MustCompile("^[[:alnum:]_]+$")
+)
+
+// WriteDOT writes the graph to w in Graphviz DOT syntax, with its graph, vertex and edge attributes.
+// Vertices, edges and attributes are sorted, so the output is deterministic.
+// In graphs of type "graph", the two entries stored for an undirected edge are written as a single "--" edge.
+// In digraphs, edges declared with "--" are written once with the attribute dir=none, as Graphviz only allows "->".
+// Note that the output follows the Graphviz grammar, which differs from the subset accepted by Parse: use
+// WriteParsableDOT to write a graph which Parse can read back.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	return g.writeDOT(w, nil)
+}
+
+// WriteClusteredDOT writes the graph like WriteDOT, declaring the vertices of every cluster (e.g. the result of
+// Communities) inside a "subgraph cluster_<i>" block, which Graphviz draws as a box around them. Vertices belonging
+// to no cluster are declared outside of any block; names which are not vertices of the graph are ignored.
+func (g *Graph) WriteClusteredDOT(w io.Writer, clusters [][]string) error {
+	return g.writeDOT(w, clusters)
+}
+
+func (g *Graph) writeDOT(w io.Writer, clusters [][]string) error {
+	writer := bufio.NewWriter(w)
+	graphType, edgeOp := "digraph", "->"
+	if g.Type == "graph" {
+		graphType, edgeOp = "graph", "--"
+	}
+	fmt.Fprintf(writer, "%v %v {\n", graphType, quoteID(g.Name))
+	for _, key := range sortedKeys(g.graphAttributes) {
+		fmt.Fprintf(writer, "\t%v=%v;\n", quoteID(key), formatValue(g.graphAttributes[key]))
+	}
+
+	names := g.topology().names
+	clustered := make(map[string]bool)
+	for i, cluster := range clusters {
+		fmt.Fprintf(writer, "\tsubgraph cluster_%v {\n", i)
+		members := append([]string{}, cluster...)
+		sort.Strings(members)
+		for _, name := range members {
+			if _, exists := g.vertexMap[name]; exists && !clustered[name] {
+				clustered[name] = true
+				fmt.Fprintf(writer, "\t\t%v%v;\n", quoteID(name), formatAttributes(g.vertexAttributes[name], nil))
+			}
+		}
+		fmt.Fprintln(writer, "\t}")
+	}
+	for _, name := range names {
+		if !clustered[name] {
+			fmt.Fprintf(writer, "\t%v%v;\n", quoteID(name), formatAttributes(g.vertexAttributes[name], nil))
+		}
+	}
+	for _, edge := range g.dotEdges(names) {
+		var extra map[string]interface{}
+		if edge.undirected && edgeOp == "->" {
+			extra = map[string]interface{}{"dir": "none"}
+		}
+		fmt.Fprintf(writer, "\t%v %v %v%v;\n", quoteID(edge.origin), edgeOp, quoteID(edge.target),
+			formatAttributes(g.edgeAttributes[edge.origin][edge.target], extra))
+	}
+	fmt.Fprintln(writer, "}")
+	return writer.Flush()
+}
+
+// WriteParsableDOT writes the graph to w in the subset of DOT accepted by Parse, so that it can be read back along
+// with its vertex and edge attributes. Unlike Graphviz, Parse expects edge attributes between the edge operator and
+// the target, and vertex attributes next to any occurrence of the vertex in an edge statement; they are written
+// after its first one. Vertices without edges are written as vertex statements. Values are read back with the types
+// Parse infers, e.g. the string "1" becomes an int. Graph attributes are omitted, as Parse doesn't read them.
+// An error is returned, before anything is written, if the graph holds a name Parse doesn't accept or a string value
+// Parse would alter.
+func (g *Graph) WriteParsableDOT(w io.Writer) error {
+	names := g.topology().names
+	edges := g.dotEdges(names)
+	if !parsableIDRe.MatchString(g.Name) {
+		return fmt.Errorf("WriteParsableDOT() of graph %q: graph name is not alphanumeric", g.Name)
+	}
+	connected := make(map[string]bool, len(names))
+	for _, edge := range edges {
+		connected[edge.origin], connected[edge.target] = true, true
+	}
+	for _, name := range names {
+		if !parsableIDRe.MatchString(name) {
+			return fmt.Errorf("WriteParsableDOT() of graph %v: vertex name %q is not alphanumeric", g.Name, name)
+		}
+		if err := checkParsableAttributes(g.vertexAttributes[name]); err != nil {
+			return fmt.Errorf("WriteParsableDOT() of graph %v, vertex %v: %v", g.Name, name, err)
+		}
+	}
+	for _, edge := range edges {
+		if err := checkParsableAttributes(g.edgeAttributes[edge.origin][edge.target]); err != nil {
+			return fmt.Errorf("WriteParsableDOT() of graph %v, edge %v -> %v: %v", g.Name, edge.origin, edge.target, err)
+		}
+	}
+
+	writer := bufio.NewWriter(w)
+	graphType := "digraph"
+	if g.Type == "graph" {
+		graphType = "graph"
+	}
+	fmt.Fprintf(writer, "%v %v {\n", graphType, g.Name)
+	declared := make(map[string]bool, len(names))
+	vertex := func(name string) string {
+		if declared[name] {
+			return name
+		}
+		declared[name] = true
+		return name + formatParsableAttributes(g.vertexAttributes[name])
+	}
+	for _, name := range names {
+		if !connected[name] {
+			fmt.Fprintf(writer, "\t%v;\n", vertex(name))
+		}
+	}
+	for _, edge := range edges {
+		edgeOp := "->"
+		if edge.undirected {
+			edgeOp = "--"
+		}
+		origin := vertex(edge.origin)
+		fmt.Fprintf(writer, "\t%v %v%v %v;\n", origin, edgeOp,
+			formatParsableAttributes(g.edgeAttributes[edge.origin][edge.target]), vertex(edge.target))
+	}
+	fmt.Fprintln(writer, "}")
+	return writer.Flush()
+}
+
+// checkParsableAttributes returns an error if Parse can't read back an attribute name or value. Parse strips
+// comments before anything else, and trims quotes, brackets, commas and whitespace around values.
+func checkParsableAttributes(attributes map[string]interface{}) error {
+	for _, key := range sortedKeys(attributes) {
+		if !parsableAttributeRe.MatchString(key) {
+			return fmt.Errorf("attribute name %q is not alphanumeric", key)
+		}
+		value, isString := attributes[key].(string)
+		if !isString {
+			continue
+		}
+		if strings.ContainsAny(value, "\"\n") || strings.Contains(value, "//") || strings.Contains(value, "/*") ||
+			value != strings.Trim(value, " ,\"\\]\t") {
+			return fmt.Errorf("value %q of attribute %v can't be read back", value, key)
+		}
+	}
+	return nil
+}
+
+// formatParsableAttributes renders attributes as an attribute list accepted by Parse, quoting every string.
+func formatParsableAttributes(attributes map[string]interface{}) string {
+	if len(attributes) == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, len(attributes))
+	for _, key := range sortedKeys(attributes) {
+		value := attributes[key]
+		switch v := value.(type) {
+		case string:
+			pairs = append(pairs, key+"=\""+v+"\"")
+		case float64:
+			pairs = append(pairs, key+"="+formatFloat(v))
+		default:
+			pairs = append(pairs, key+"="+fmt.Sprint(value))
+		}
+	}
+	return " [" + strings.Join(pairs, ", ") + "]"
+}
+
+type dotEdge struct {
+	origin     string
+	target     string
+	undirected bool
+}
+
+// dotEdges lists the edges to write, sorted by origin and target. In graphs of type "graph", every edge is
+// undirected; pairs of opposite entries are merged, keeping parallel edges.
+func (g *Graph) dotEdges(names []string) []dotEdge {
+	counts := make(map[Edge]int)
+	for origin, neighbors := range g.adjacencyMap {
+		for _, neighbor := range neighbors {
+			counts[Edge{Origin: origin, Target: neighbor.Name()}]++
+		}
+	}
+	var edges []dotEdge
+	for _, origin := range names {
+		targets := make([]string, 0, len(g.adjacencyMap[origin]))
+		for _, neighbor := range g.adjacencyMap[origin] {
+			targets = append(targets, neighbor.Name())
+		}
+		sort.Strings(targets)
+		for i, target := range targets {
+			if i > 0 && target == targets[i-1] {
+				continue
+			}
+			count, reverse := counts[Edge{Origin: origin, Target: target}], counts[Edge{Origin: target, Target: origin}]
+			undirected := g.Type == "graph" || g.undirectedEdges[origin][target]
+			if undirected && origin == target && g.undirectedEdges[origin][target] {
+				// both directions of a self loop declared with "--" are stored as entries of the same list
+				count = (count + 1) / 2
+			}
+			if undirected && origin != target {
+				if reverse > 0 && target < origin {
+					// written along with target -> origin
+					continue
+				}
+				if reverse > count {
+					count = reverse
+				}
+			}
+			for ; count > 0; count-- {
+				edges = append(edges, dotEdge{origin: origin, target: target, undirected: undirected})
+			}
+		}
+	}
+	return edges
+}
+
+// quoteID returns id as is if it is a valid unquoted DOT identifier, or as a quoted string otherwise.
+func quoteID(id string) string {
+	if (plainIDRe.MatchString(id) || numeralIDRe.MatchString(id)) && !keywordIDs[strings.ToLower(id)] {
+		return id
+	}
+	return strconv.Quote(id)
+}
+
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return quoteID(v)
+	case float64:
+		return quoteID(formatFloat(v))
+	}
+	return quoteID(fmt.Sprint(value))
+}
+
+// formatFloat formats v with a decimal point, so that Parse reads it back as a float rather than an int.
+func formatFloat(v float64) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	formatted := strconv.FormatFloat(v, 'g', -1, 64)
+	if strings.ContainsRune(formatted, 'e') {
+		formatted = strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	if !strings.ContainsRune(formatted, '.') {
+		formatted += ".0"
+	}
+	return formatted
+}
+
+// formatAttributes renders the union of attributes and extra (which takes precedence) as a DOT attribute list.
+func formatAttributes(attributes map[string]interface{}, extra map[string]interface{}) string {
+	if len(attributes)+len(extra) == 0 {
+		return ""
+	}
+	merged := make(map[string]interface{}, len(attributes)+len(extra))
+	for key, value := range attributes {
+		merged[key] = value
+	}
+	for key, value := range extra {
+		merged[key] = value
+	}
+	pairs := make([]string, 0, len(merged))
+	for _, key := range sortedKeys(merged) {
+		pairs = append(pairs, quoteID(key)+"="+formatValue(merged[key]))
+	}
+	return " [" + strings.Join(pairs, ", ") + "]"
+}
+
+func sortedKeys(attributes map[string]interface{}) []string {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}